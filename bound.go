@@ -1,5 +1,7 @@
 package granges
 
+import "fmt"
+
 // BoundType indicates whether an endpoint of some range is contained in the
 // range itself ("closed") or not ("open"). If a range is unbounded on a side,
 // it is neither open nor closed on that side; the bound simply does not exist.
@@ -11,3 +13,66 @@ const (
 	OPEN   BoundType = iota // open interval: ()
 	CLOSED                  // closed interval: []
 )
+
+// Bounds is a pointer-based representation of a range for exchanging ranges
+// with external APIs, where a nil endpoint means the range is unbounded on
+// that side. The inclusive flags tell whether the endpoints are contained in
+// the range, and are meaningless for unbounded sides.
+type Bounds[C any] struct {
+	Lower          *C
+	LowerInclusive bool
+	Upper          *C
+	UpperInclusive bool
+}
+
+// ToBounds converts r to its Bounds representation. An error will be
+// returned if r is invalid.
+func ToBounds[C any](r Range[C]) (Bounds[C], error) {
+	var b Bounds[C]
+	if r.IsInvalid() {
+		return b, fmt.Errorf("cannot convert invalid range to bounds")
+	}
+	if r.HasLowerBound() {
+		lower := r.LowerEndpoint()
+		b.Lower, b.LowerInclusive = &lower, r.LowerBoundType() == CLOSED
+	}
+	if r.HasUpperBound() {
+		upper := r.UpperEndpoint()
+		b.Upper, b.UpperInclusive = &upper, r.UpperBoundType() == CLOSED
+	}
+	return b, nil
+}
+
+// FromBounds converts b to a range, with the same validation as NewE. Bounds
+// with nil endpoints on both sides yield All.
+//
+// An invalid range with an error will be returned if the lower endpoint is
+// greater than the upper one, or if they are equal and both exclusive.
+func FromBounds[C any](b Bounds[C]) (Range[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Invalid[C](), err
+	}
+	return fromBounds(b, order)
+}
+
+// fromBounds converts b to a range whose cuts carry order.
+func fromBounds[C any](b Bounds[C], order *ordering[C]) (Range[C], error) {
+	lowerBound := Cut[C]{cutType: BelowAll, order: order}
+	upperBound := lowerBound.aboveAll()
+	if b.Lower != nil {
+		if b.LowerInclusive {
+			lowerBound = lowerBound.belowValue(*b.Lower)
+		} else {
+			lowerBound = lowerBound.aboveValue(*b.Lower)
+		}
+	}
+	if b.Upper != nil {
+		if b.UpperInclusive {
+			upperBound = upperBound.aboveValue(*b.Upper)
+		} else {
+			upperBound = upperBound.belowValue(*b.Upper)
+		}
+	}
+	return create(lowerBound, upperBound)
+}
@@ -0,0 +1,123 @@
+package granges_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func ptr[C any](v C) *C { return &v }
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		R granges.Range[int]
+		B granges.Bounds[int]
+	}{
+		{R: granges.Open(1, 5), B: granges.Bounds[int]{Lower: ptr(1), Upper: ptr(5)}},
+		{R: granges.Closed(1, 5), B: granges.Bounds[int]{Lower: ptr(1), LowerInclusive: true, Upper: ptr(5), UpperInclusive: true}},
+		{R: granges.OpenClosed(1, 5), B: granges.Bounds[int]{Lower: ptr(1), Upper: ptr(5), UpperInclusive: true}},
+		{R: granges.ClosedOpen(1, 5), B: granges.Bounds[int]{Lower: ptr(1), LowerInclusive: true, Upper: ptr(5)}},
+		{R: granges.GreaterThan(1), B: granges.Bounds[int]{Lower: ptr(1)}},
+		{R: granges.AtLeast(1), B: granges.Bounds[int]{Lower: ptr(1), LowerInclusive: true}},
+		{R: granges.LessThan(5), B: granges.Bounds[int]{Upper: ptr(5)}},
+		{R: granges.AtMost(5), B: granges.Bounds[int]{Upper: ptr(5), UpperInclusive: true}},
+		{R: granges.All[int](), B: granges.Bounds[int]{}},
+		{R: granges.ClosedOpen(3, 3), B: granges.Bounds[int]{Lower: ptr(3), LowerInclusive: true, Upper: ptr(3)}},
+	}
+
+	for _, tt := range tests {
+		b, err := granges.ToBounds(tt.R)
+		assert.NoError(t, err)
+		assert.EqualValues(t, tt.B, b, "ToBounds(%v)", tt.R)
+
+		r, err := granges.FromBounds(tt.B)
+		assert.NoError(t, err)
+		assert.True(t, tt.R.Equal(r), "FromBounds(%+v) = %v, want %v", tt.B, r, tt.R)
+	}
+
+	// inclusive flags of unbounded sides are ignored
+	r, err := granges.FromBounds(granges.Bounds[int]{LowerInclusive: true, UpperInclusive: true})
+	assert.NoError(t, err)
+	assert.True(t, granges.All[int]().Equal(r))
+}
+
+func TestBounds_invalid(t *testing.T) {
+	_, err := granges.ToBounds(granges.Invalid[int]())
+	assert.Error(t, err)
+
+	r, err := granges.FromBounds(granges.Bounds[int]{Lower: ptr(5), LowerInclusive: true, Upper: ptr(1)})
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+
+	r, err = granges.FromBounds(granges.Bounds[int]{Lower: ptr(3), Upper: ptr(3)})
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+}
+
+func TestRange_Key(t *testing.T) {
+	assert.Equal(t, granges.RangeKey[int]{Lower: 1, LowerType: granges.CLOSED, Upper: 5, UpperType: granges.OPEN}, granges.ClosedOpen(1, 5).Key())
+	assert.Equal(t, granges.RangeKey[int]{LowerType: granges.Unbounded, Upper: 5, UpperType: granges.CLOSED}, granges.AtMost(5).Key())
+	assert.Equal(t, granges.RangeKey[int]{LowerType: granges.Unbounded, UpperType: granges.Unbounded}, granges.All[int]().Key())
+	assert.Equal(t, granges.RangeKey[int]{Invalid: true}, granges.Invalid[int]().Key())
+
+	ranges := []granges.Range[int]{
+		granges.Closed(1, 5),
+		granges.ClosedOpen(1, 5),
+		granges.Closed(1, 5),
+		granges.AtLeast(0),
+		granges.GreaterThan(0),
+		granges.AtLeast(0),
+		granges.LessThan(0),
+		granges.All[int](),
+		granges.Invalid[int](),
+		granges.ClosedOpen(0, 0),
+		granges.Invalid[int](),
+	}
+	for _, a := range ranges {
+		for _, b := range ranges {
+			assert.Equal(t, a.Equal(b), a.Key() == b.Key(), "%v and %v", a, b)
+		}
+	}
+
+	seen := make(map[granges.RangeKey[int]]int)
+	for _, r := range ranges {
+		seen[r.Key()]++
+	}
+	assert.Len(t, seen, 8)
+	assert.Equal(t, 2, seen[granges.Closed(1, 5).Key()])
+	assert.Equal(t, 2, seen[granges.Invalid[int]().Key()])
+
+	// negative zero is equal to zero
+	assert.Equal(t, granges.Closed(0.0, 1).Key(), granges.Closed(math.Copysign(0, -1), 1).Key())
+}
+
+func TestRange_Kind(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want granges.RangeKind
+		Name string
+	}{
+		{R: granges.Open(1, 5), Want: granges.KindOpen, Name: "Open"},
+		{R: granges.Closed(1, 5), Want: granges.KindClosed, Name: "Closed"},
+		{R: granges.OpenClosed(1, 5), Want: granges.KindOpenClosed, Name: "OpenClosed"},
+		{R: granges.ClosedOpen(1, 5), Want: granges.KindClosedOpen, Name: "ClosedOpen"},
+		{R: granges.GreaterThan(1), Want: granges.KindGreaterThan, Name: "GreaterThan"},
+		{R: granges.AtLeast(1), Want: granges.KindAtLeast, Name: "AtLeast"},
+		{R: granges.LessThan(5), Want: granges.KindLessThan, Name: "LessThan"},
+		{R: granges.AtMost(5), Want: granges.KindAtMost, Name: "AtMost"},
+		{R: granges.All[int](), Want: granges.KindAll, Name: "All"},
+		{R: granges.Singleton(4), Want: granges.KindClosed, Name: "Closed"},
+		{R: granges.ClosedOpen(4, 4), Want: granges.KindClosedOpen, Name: "ClosedOpen"},
+		{R: granges.OpenClosed(4, 4), Want: granges.KindOpenClosed, Name: "OpenClosed"},
+		{R: granges.Invalid[int](), Want: granges.KindInvalid, Name: "Invalid"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, tt.R.Kind(), "%v.Kind()", tt.R)
+		assert.Equal(t, tt.Name, tt.R.Kind().String())
+	}
+	assert.Equal(t, "unknown", granges.RangeKind(42).String())
+}
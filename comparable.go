@@ -5,3 +5,20 @@ type Comparable interface {
 		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
 		~float32 | ~float64 | ~string
 }
+
+// Integer is the subset of Comparable permitting any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Float is the subset of Comparable permitting any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is the subset of Comparable permitting any numeric type, that is
+// every Comparable type except strings.
+type Number interface {
+	Integer | Float
+}
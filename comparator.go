@@ -0,0 +1,398 @@
+package granges
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Comparer is implemented by types which know how to order themselves, such
+// as time.Time. Compare returns a negative number if the receiver is less
+// than other, a positive number if it is greater, and zero if they are
+// equal.
+//
+// Ranges of Comparer types are built with the same constructors as ranges of
+// Comparable types, the ordering being detected when a range is created: a
+// Comparer is ordered by its Compare method, a Comparable type by the
+// operators. Constructors return an invalid range, or an error, for types
+// which are neither; such types need a Comparator.
+type Comparer[C any] interface {
+	Compare(other C) int
+}
+
+// ordering is a total order over the values of C, carried by the cuts of a
+// range to compare endpoints with. Orderings are compared by identity: each
+// Comparator holds its own, while the natural orderings are shared by all the
+// ranges of a type and marked as such, so that ranges ordered by any of them
+// may be combined.
+type ordering[C any] struct {
+	compare func(a, b C) int
+	natural bool
+	rank    uint64 // position among the Comparators created, 0 if natural
+}
+
+// The natural orderings of the predeclared Comparable types, which are
+// compared with the operators.
+var (
+	intOrder     = &ordering[int]{compare: compareOperators[int], natural: true}
+	int8Order    = &ordering[int8]{compare: compareOperators[int8], natural: true}
+	int16Order   = &ordering[int16]{compare: compareOperators[int16], natural: true}
+	int32Order   = &ordering[int32]{compare: compareOperators[int32], natural: true}
+	int64Order   = &ordering[int64]{compare: compareOperators[int64], natural: true}
+	uintOrder    = &ordering[uint]{compare: compareOperators[uint], natural: true}
+	uint8Order   = &ordering[uint8]{compare: compareOperators[uint8], natural: true}
+	uint16Order  = &ordering[uint16]{compare: compareOperators[uint16], natural: true}
+	uint32Order  = &ordering[uint32]{compare: compareOperators[uint32], natural: true}
+	uint64Order  = &ordering[uint64]{compare: compareOperators[uint64], natural: true}
+	float32Order = &ordering[float32]{compare: compareOperators[float32], natural: true}
+	float64Order = &ordering[float64]{compare: compareOperators[float64], natural: true}
+	stringOrder  = &ordering[string]{compare: compareOperators[string], natural: true}
+)
+
+// namedOrders holds the natural orderings of the other types, named types of
+// a Comparable kind and Comparer types, by reflect.Type, so that they are
+// created once per type.
+var namedOrders sync.Map
+
+// compareMethod compares values of a Comparer type with their Compare method.
+func compareMethod[C any](a, b C) int {
+	return any(a).(Comparer[C]).Compare(b)
+}
+
+// compareKind returns a function comparing values of a named type by their
+// underlying kind, which is only known at run time, or nil if the kind is not
+// Comparable. The kind is looked up once, when the ordering of the type is
+// created, so that comparing values is no slower than for their underlying
+// type.
+func compareKind[C any](kind reflect.Kind) func(a, b C) int {
+	switch kind {
+	case reflect.Int:
+		return compareAs[C, int]
+	case reflect.Int8:
+		return compareAs[C, int8]
+	case reflect.Int16:
+		return compareAs[C, int16]
+	case reflect.Int32:
+		return compareAs[C, int32]
+	case reflect.Int64:
+		return compareAs[C, int64]
+	case reflect.Uint:
+		return compareAs[C, uint]
+	case reflect.Uint8:
+		return compareAs[C, uint8]
+	case reflect.Uint16:
+		return compareAs[C, uint16]
+	case reflect.Uint32:
+		return compareAs[C, uint32]
+	case reflect.Uint64:
+		return compareAs[C, uint64]
+	case reflect.Float32:
+		return compareAs[C, float32]
+	case reflect.Float64:
+		return compareAs[C, float64]
+	case reflect.String:
+		return compareAs[C, string]
+	default:
+		return nil
+	}
+}
+
+// compareAs compares values of C as values of U with the operators. U must be
+// the underlying type of C, as selected by compareKind from the kind of C, so
+// that both have the same memory layout.
+func compareAs[C any, U Comparable](a, b C) int {
+	return compareOperators(*(*U)(unsafe.Pointer(&a)), *(*U)(unsafe.Pointer(&b)))
+}
+
+// compareNothing is the comparison of cuts of a type with no natural ordering
+// that carry no ordering: every value is equal to every other. It is a last
+// resort so that comparing cuts never panics, as the constructors of ranges
+// and cuts reject such types.
+func compareNothing[C any](C, C) int {
+	return 0
+}
+
+func compareOperators[C Comparable](a, b C) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// naturalOrderOf returns the natural ordering of C, or nil and false if C has
+// none and its ranges need a Comparator. Comparable types are compared with
+// the operators, unless they are named types implementing Comparer, whose
+// Compare method is used instead.
+func naturalOrderOf[C any]() (*ordering[C], bool) {
+	var order any
+	switch any(*new(C)).(type) {
+	case int:
+		order = intOrder
+	case int8:
+		order = int8Order
+	case int16:
+		order = int16Order
+	case int32:
+		order = int32Order
+	case int64:
+		order = int64Order
+	case uint:
+		order = uintOrder
+	case uint8:
+		order = uint8Order
+	case uint16:
+		order = uint16Order
+	case uint32:
+		order = uint32Order
+	case uint64:
+		order = uint64Order
+	case float32:
+		order = float32Order
+	case float64:
+		order = float64Order
+	case string:
+		order = stringOrder
+	default:
+		return namedOrderOf[C]()
+	}
+	return order.(*ordering[C]), true
+}
+
+// namedOrderOf returns the natural ordering of C, which is not a predeclared
+// type, or nil and false if C has none.
+func namedOrderOf[C any]() (*ordering[C], bool) {
+	typ := reflect.TypeFor[C]()
+	if order, ok := namedOrders.Load(typ); ok {
+		return order.(*ordering[C]), true
+	}
+
+	var compare func(a, b C) int
+	if _, ok := any(*new(C)).(Comparer[C]); ok {
+		compare = compareMethod[C]
+	} else if compare = compareKind[C](typ.Kind()); compare == nil {
+		return nil, false
+	}
+	order, _ := namedOrders.LoadOrStore(typ, &ordering[C]{compare: compare, natural: true})
+	return order.(*ordering[C]), true
+}
+
+// orderOf returns the natural ordering of C, or an error if C has none.
+func orderOf[C any]() (*ordering[C], error) {
+	order, ok := naturalOrderOf[C]()
+	if !ok {
+		return nil, fmt.Errorf("cannot order values of %T: neither Comparable nor Comparer", *new(C))
+	}
+	return order, nil
+}
+
+// naturalRange returns the range from lower to upper ordered by the natural
+// ordering of C, with the same validation as NewE.
+func naturalRange[C any](lower C, lowerType BoundType, upper C, upperType BoundType) (Range[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Invalid[C](), err
+	}
+	return newRange(lower, lowerType, upper, upperType, order)
+}
+
+// naturalBelowAll returns the cut below all values ordered by the natural
+// ordering of C, and false if C has none.
+func naturalBelowAll[C any]() (Cut[C], bool) {
+	order, ok := naturalOrderOf[C]()
+	return Cut[C]{cutType: BelowAll, order: order}, ok
+}
+
+// sameOrder reports whether cuts ordered by a and b may be compared with each
+// other, that is the orderings are the same Comparator or are both natural.
+// A nil ordering, carried by cuts which are not part of a range built from
+// values such as the cuts of invalid ranges, goes with any ordering.
+func sameOrder[C any](a, b *ordering[C]) bool {
+	return a == nil || b == nil || a == b || a.natural && b.natural
+}
+
+// orderRank returns the rank ranges ordered by order are sorted by before
+// their bounds, so that ranges ordered differently never compare the same:
+// 0 for the natural orderings, and the rank of a Comparator, in the order the
+// Comparators were created, for the others.
+func orderRank[C any](order *ordering[C]) uint64 {
+	if order == nil {
+		return 0
+	}
+	return order.rank
+}
+
+// isNatural reports whether order compares values with the operators, the
+// nil ordering falling back to them.
+func isNatural[C any](order *ordering[C]) bool {
+	return order == nil || order.natural
+}
+
+// Comparator orders the values of C with a comparison function, so that
+// ranges may be built over types which are neither Comparable nor Comparer,
+// such as *big.Int, or over types to be ordered in some other way.
+//
+// Ranges built by a Comparator carry it, and every operation on them orders
+// values with it. Two ranges may only be combined if they were built by the
+// same Comparator, thus a Comparator is meant to be created once and shared:
+//
+//	byValue := granges.NewComparator((*big.Int).Cmp)
+//	r := byValue.ClosedOpen(big.NewInt(1), big.NewInt(10))
+type Comparator[C any] struct {
+	order ordering[C]
+}
+
+// comparators counts the Comparators created, to rank them.
+var comparators atomic.Uint64
+
+// NewComparator returns a Comparator ordering values with compare, which
+// returns a negative number if a is less than b, a positive number if a is
+// greater than b, and zero if they are equal, like cmp.Compare does.
+func NewComparator[C any](compare func(a, b C) int) *Comparator[C] {
+	return &Comparator[C]{order: ordering[C]{compare: compare, rank: comparators.Add(1)}}
+}
+
+// NewWithComparator returns a range that contains any value from lower to
+// upper ordered by comparator, where each endpoint may be either inclusive
+// (closed) or exclusive (open).
+//
+// An invalid range will be returned if lower is greater than upper.
+func NewWithComparator[C any](lower C, lowerType BoundType, upper C, upperType BoundType, comparator *Comparator[C]) Range[C] {
+	r, _ := NewWithComparatorE(lower, lowerType, upper, upperType, comparator)
+	return r
+}
+
+// NewWithComparatorE returns a range that contains any value from lower to
+// upper ordered by comparator, where each endpoint may be either inclusive
+// (closed) or exclusive (open).
+//
+// An invalid range with an error will be returned if lower is greater than
+// upper.
+func NewWithComparatorE[C any](lower C, lowerType BoundType, upper C, upperType BoundType, comparator *Comparator[C]) (Range[C], error) {
+	return newRange(lower, lowerType, upper, upperType, &comparator.order)
+}
+
+// Open returns the range (lower..upper) ordered by c. An invalid range will
+// be returned if lower is greater than or equal to upper.
+func (c *Comparator[C]) Open(lower, upper C) Range[C] {
+	r, _ := c.OpenE(lower, upper)
+	return r
+}
+
+// OpenE returns the range (lower..upper) ordered by c. An invalid range with
+// an error will be returned if lower is greater than or equal to upper.
+func (c *Comparator[C]) OpenE(lower, upper C) (Range[C], error) {
+	return newRange(lower, OPEN, upper, OPEN, &c.order)
+}
+
+// Closed returns the range [lower..upper] ordered by c. An invalid range will
+// be returned if lower is greater than upper.
+func (c *Comparator[C]) Closed(lower, upper C) Range[C] {
+	r, _ := c.ClosedE(lower, upper)
+	return r
+}
+
+// ClosedE returns the range [lower..upper] ordered by c. An invalid range
+// with an error will be returned if lower is greater than upper.
+func (c *Comparator[C]) ClosedE(lower, upper C) (Range[C], error) {
+	return newRange(lower, CLOSED, upper, CLOSED, &c.order)
+}
+
+// ClosedOpen returns the range [lower..upper) ordered by c. An invalid range
+// will be returned if lower is greater than upper.
+func (c *Comparator[C]) ClosedOpen(lower, upper C) Range[C] {
+	r, _ := c.ClosedOpenE(lower, upper)
+	return r
+}
+
+// ClosedOpenE returns the range [lower..upper) ordered by c. An invalid range
+// with an error will be returned if lower is greater than upper.
+func (c *Comparator[C]) ClosedOpenE(lower, upper C) (Range[C], error) {
+	return newRange(lower, CLOSED, upper, OPEN, &c.order)
+}
+
+// OpenClosed returns the range (lower..upper] ordered by c. An invalid range
+// will be returned if lower is greater than upper.
+func (c *Comparator[C]) OpenClosed(lower, upper C) Range[C] {
+	r, _ := c.OpenClosedE(lower, upper)
+	return r
+}
+
+// OpenClosedE returns the range (lower..upper] ordered by c. An invalid range
+// with an error will be returned if lower is greater than upper.
+func (c *Comparator[C]) OpenClosedE(lower, upper C) (Range[C], error) {
+	return newRange(lower, OPEN, upper, CLOSED, &c.order)
+}
+
+// LessThan returns the range (-∞..upper) ordered by c.
+func (c *Comparator[C]) LessThan(upper C) Range[C] {
+	return Range[C]{lowerBound: Cut[C]{cutType: BelowAll, order: &c.order}, upperBound: Cut[C]{cutType: BelowValue, endpoint: upper, order: &c.order}}
+}
+
+// AtMost returns the range (-∞..upper] ordered by c.
+func (c *Comparator[C]) AtMost(upper C) Range[C] {
+	return Range[C]{lowerBound: Cut[C]{cutType: BelowAll, order: &c.order}, upperBound: Cut[C]{cutType: AboveValue, endpoint: upper, order: &c.order}}
+}
+
+// GreaterThan returns the range (lower..+∞) ordered by c.
+func (c *Comparator[C]) GreaterThan(lower C) Range[C] {
+	return Range[C]{lowerBound: Cut[C]{cutType: AboveValue, endpoint: lower, order: &c.order}, upperBound: Cut[C]{cutType: AboveAll, order: &c.order}}
+}
+
+// AtLeast returns the range [lower..+∞) ordered by c.
+func (c *Comparator[C]) AtLeast(lower C) Range[C] {
+	return Range[C]{lowerBound: Cut[C]{cutType: BelowValue, endpoint: lower, order: &c.order}, upperBound: Cut[C]{cutType: AboveAll, order: &c.order}}
+}
+
+// All returns the range (-∞..+∞) ordered by c.
+func (c *Comparator[C]) All() Range[C] {
+	return Range[C]{lowerBound: Cut[C]{cutType: BelowAll, order: &c.order}, upperBound: Cut[C]{cutType: AboveAll, order: &c.order}}
+}
+
+// Singleton returns the range [value..value] ordered by c.
+func (c *Comparator[C]) Singleton(value C) Range[C] {
+	return c.Closed(value, value)
+}
+
+// newRange returns the range from lower to upper whose cuts carry order, with
+// the same validation as NewE.
+func newRange[C any](lower C, lowerType BoundType, upper C, upperType BoundType, order *ordering[C]) (Range[C], error) {
+	lowerBound := Cut[C]{cutType: BelowValue, endpoint: lower, order: order}
+	if lowerType == OPEN {
+		lowerBound.cutType = AboveValue
+	}
+	upperBound := Cut[C]{cutType: AboveValue, endpoint: upper, order: order}
+	if upperType == OPEN {
+		upperBound.cutType = BelowValue
+	}
+	return create(lowerBound, upperBound)
+}
+
+// decodingOrder returns the ordering of the ranges decoded into r, that is
+// the one r carries if it was built by a Comparator, or else the natural
+// ordering of C. An error will be returned if C has no natural ordering.
+func (r Range[C]) decodingOrder() (*ordering[C], error) {
+	if order := r.order(); !isNatural(order) {
+		return order, nil
+	}
+	return orderOf[C]()
+}
+
+// order returns the ordering carried by the cuts of r, which is nil for the
+// zero Range and invalid ranges.
+func (r Range[C]) order() *ordering[C] {
+	return r.lowerBound.order
+}
+
+// checkOrder returns an error if r and other may not be combined because
+// they are not ordered the same way.
+func (r Range[C]) checkOrder(other Range[C]) error {
+	if !sameOrder(r.order(), other.order()) {
+		return fmt.Errorf("cannot combine %s and %s: %w", r, other, ErrComparatorMismatch)
+	}
+	return nil
+}
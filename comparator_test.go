@@ -0,0 +1,316 @@
+package granges_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+var (
+	byTime = granges.NewComparator(time.Time.Compare)
+	epoch  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func day(n int) time.Time {
+	return epoch.AddDate(0, 0, n)
+}
+
+func TestComparator(t *testing.T) {
+	r := byTime.ClosedOpen(day(1), day(5))
+	assert.False(t, r.IsInvalid())
+	assert.False(t, r.Contains(day(0)))
+	assert.True(t, r.Contains(day(1)))
+	assert.True(t, r.Contains(day(4)))
+	assert.False(t, r.Contains(day(5)))
+	assert.Equal(t, day(1), r.LowerEndpoint())
+	assert.Equal(t, granges.OPEN, r.UpperBoundType())
+
+	assert.True(t, byTime.Open(day(1), day(2)).Contains(day(1).Add(time.Hour)))
+	assert.True(t, byTime.Closed(day(1), day(1)).Equal(byTime.Singleton(day(1))))
+	assert.True(t, byTime.OpenClosed(day(1), day(2)).Contains(day(2)))
+	assert.True(t, byTime.LessThan(day(1)).Contains(day(0)))
+	assert.False(t, byTime.AtMost(day(1)).Contains(day(2)))
+	assert.True(t, byTime.GreaterThan(day(1)).Contains(day(2)))
+	assert.True(t, byTime.AtLeast(day(1)).Contains(day(1)))
+	assert.True(t, byTime.All().Contains(day(-100)))
+
+	_, err := byTime.OpenE(day(2), day(2))
+	assert.Error(t, err)
+	assert.True(t, byTime.Closed(day(2), day(1)).IsInvalid())
+}
+
+func TestNewWithComparator(t *testing.T) {
+	r := granges.NewWithComparator(day(1), granges.OPEN, day(3), granges.CLOSED, byTime)
+	assert.True(t, r.Equal(byTime.OpenClosed(day(1), day(3))))
+
+	r, err := granges.NewWithComparatorE(day(3), granges.CLOSED, day(1), granges.CLOSED, byTime)
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+}
+
+func TestComparator_operations(t *testing.T) {
+	a := byTime.Closed(day(1), day(5))
+	b := byTime.Open(day(3), day(7))
+
+	assert.True(t, a.IsConnected(b))
+	assert.True(t, a.Intersects(b))
+	assert.True(t, a.Intersection(b).Equal(byTime.OpenClosed(day(3), day(5))))
+	assert.True(t, a.Span(b).Equal(byTime.ClosedOpen(day(1), day(7))))
+	assert.True(t, a.Encloses(byTime.Singleton(day(2))))
+	assert.True(t, a.Gap(byTime.AtLeast(day(6))).Equal(byTime.Open(day(5), day(6))))
+
+	before, after, err := a.SplitAt(day(2), granges.OPEN)
+	assert.NoError(t, err)
+	assert.True(t, before.Equal(byTime.ClosedOpen(day(1), day(2))))
+	assert.True(t, after.Equal(byTime.Closed(day(2), day(5))))
+
+	s := granges.NewRangeSet(a, byTime.Closed(day(10), day(12)), b)
+	assert.Len(t, s.AsRanges(), 2)
+	assert.True(t, s.Contains(day(6)))
+	assert.False(t, s.Contains(day(8)))
+}
+
+func TestComparator_order(t *testing.T) {
+	// strings ordered by length, then lexicographically
+	byLength := granges.NewComparator(func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+	r := byLength.Closed("b", "aaa")
+	assert.True(t, r.Contains("zz"))
+	assert.False(t, r.Contains("a"))
+	assert.False(t, r.Contains("aaaa"))
+	assert.True(t, granges.Closed("b", "aaa").IsInvalid())
+}
+
+func TestComparator_mismatch(t *testing.T) {
+	other := granges.NewComparator(time.Time.Compare)
+	a := byTime.Closed(day(1), day(5))
+	b := other.Closed(day(3), day(7))
+
+	_, err := a.IntersectionE(b)
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+	_, err = a.SpanE(b)
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+	_, err = a.GapE(other.AtLeast(day(6)))
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+	_, err = a.UnionE(b)
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+	assert.False(t, a.IsConnected(b))
+	assert.False(t, a.Intersects(b))
+	assert.False(t, a.Encloses(other.Singleton(day(2))))
+	assert.False(t, a.Equal(other.Closed(day(1), day(5))))
+
+	// ranges of Comparable types built by a comparator and by the plain
+	// constructors are ordered differently as well
+	byInt := granges.NewComparator(func(a, b int) int { return a - b })
+	_, err = byInt.Closed(1, 5).SpanE(granges.Closed(3, 7))
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+}
+
+func TestComparator_unmarshalJSON(t *testing.T) {
+	data, err := json.Marshal(byTime.ClosedOpen(day(1), day(5)))
+	assert.NoError(t, err)
+
+	decoded := byTime.All()
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.Equal(byTime.ClosedOpen(day(1), day(5))))
+
+	// without a comparator to decode with, ranges of Comparer types are
+	// ordered by their Compare method
+	var r granges.Range[time.Time]
+	assert.NoError(t, json.Unmarshal(data, &r))
+	assert.True(t, r.Equal(granges.ClosedOpen(day(1), day(5))))
+}
+
+type version struct {
+	major, minor int
+}
+
+func (v version) Compare(other version) int {
+	if v.major != other.major {
+		return v.major - other.major
+	}
+	return v.minor - other.minor
+}
+
+// countdown is ordered by its Compare method rather than by the operators.
+type countdown int
+
+func (c countdown) Compare(other countdown) int {
+	return int(other - c)
+}
+
+func TestComparer(t *testing.T) {
+	r := granges.ClosedOpen(version{1, 2}, version{2, 0})
+	assert.False(t, r.IsInvalid())
+	assert.True(t, r.Contains(version{1, 10}))
+	assert.False(t, r.Contains(version{1, 1}))
+	assert.False(t, r.Contains(version{2, 0}))
+	assert.True(t, granges.Closed(version{2, 0}, version{1, 0}).IsInvalid())
+
+	span := r.Span(granges.AtLeast(version{3, 0}))
+	assert.True(t, span.Equal(granges.AtLeast(version{1, 2})))
+
+	days := granges.Closed(day(1), day(5))
+	assert.True(t, days.Intersection(granges.GreaterThan(day(3))).Equal(granges.OpenClosed(day(3), day(5))))
+
+	// the Compare method takes precedence over the operators
+	countdowns := granges.Closed[countdown](10, 1)
+	assert.False(t, countdowns.IsInvalid())
+	assert.True(t, countdowns.Contains(5))
+	assert.False(t, countdowns.Contains(11))
+}
+
+func TestComparer_namedTypes(t *testing.T) {
+	type celsius float64
+	r := granges.Open[celsius](-1.5, 2)
+	assert.True(t, r.Contains(0))
+	assert.False(t, r.Contains(2))
+	assert.True(t, r.Span(granges.Singleton[celsius](3)).Equal(granges.OpenClosed[celsius](-1.5, 3)))
+}
+
+func TestComparer_namedKinds(t *testing.T) {
+	type level int8
+	type port uint16
+	type ratio float32
+	type tag string
+
+	assert.True(t, granges.Closed[level](-3, 3).Contains(-1))
+	assert.False(t, granges.Closed[level](-3, 3).Contains(4))
+	assert.True(t, granges.ClosedOpen[port](80, 443).Contains(80))
+	assert.False(t, granges.ClosedOpen[port](80, 443).Contains(443))
+	assert.True(t, granges.Open[ratio](0.25, 0.5).Contains(0.3))
+	assert.True(t, granges.Closed[tag]("a", "c").Contains("b"))
+	assert.True(t, granges.Closed[port](443, 80).IsInvalid())
+
+	// every kind is compared as its underlying type, whatever its width
+	type (
+		i   int
+		i16 int16
+		i32 int32
+		u   uint
+		u8  uint8
+		u32 uint32
+		u64 uint64
+		f64 float64
+	)
+	assert.True(t, granges.Closed[i](math.MinInt, -1).Contains(math.MinInt+1))
+	assert.True(t, granges.Closed[i16](-300, 300).Contains(-299))
+	assert.False(t, granges.Closed[i32](-1<<20, 0).Contains(1))
+	assert.True(t, granges.Closed[time.Duration](-time.Hour, time.Hour).Contains(-time.Minute))
+	assert.False(t, granges.Closed[time.Duration](-time.Hour, time.Hour).Contains(2*time.Hour))
+	assert.True(t, granges.AtLeast[u](1<<40).Contains(math.MaxUint))
+	assert.True(t, granges.Closed[u8](0, 255).Contains(200))
+	assert.False(t, granges.LessThan[u32](1<<31).Contains(1<<31))
+	assert.True(t, granges.GreaterThan[u64](1<<63).Contains(math.MaxUint64))
+	assert.True(t, granges.Open(f64(math.Inf(-1)), -1.5).Contains(-2))
+}
+
+func TestComparer_unordered(t *testing.T) {
+	type point struct{ x, y int }
+	r, err := granges.ClosedE(point{1, 2}, point{3, 4})
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+	assert.True(t, granges.AtLeast(point{1, 2}).IsInvalid())
+	assert.True(t, granges.All[point]().IsInvalid())
+
+	// cuts at values of such types cannot be compared, rather than giving
+	// meaningless answers
+	_, err = granges.NewBelowValueE(point{5, 0})
+	assert.Error(t, err)
+	_, err = granges.NewAboveValueE(point{5, 0})
+	assert.Error(t, err)
+	assert.PanicsWithError(t, err.Error(), func() { granges.NewBelowValue(point{5, 0}) })
+	assert.Panics(t, func() { granges.NewAboveValue(point{5, 0}) })
+	assert.True(t, granges.NewBelowAll[point]().IsLessThan(point{1, 2}))
+
+	// a comparator orders such types
+	byX := granges.NewComparator(func(a, b point) int { return a.x - b.x })
+	assert.True(t, byX.Closed(point{1, 2}, point{3, 4}).Contains(point{2, 0}))
+}
+
+func TestComparer_comparatorMismatch(t *testing.T) {
+	_, err := granges.Closed(day(1), day(5)).IntersectionE(byTime.Closed(day(3), day(7)))
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+}
+
+func TestComparator_api(t *testing.T) {
+	var m granges.RangeMap[time.Time, string]
+	m.Put(byTime.ClosedOpen(day(1), day(5)), "busy")
+	m.Put(byTime.ClosedOpen(day(3), day(4)), "meeting")
+	v, ok := m.Get(day(3))
+	assert.True(t, ok)
+	assert.Equal(t, "meeting", v)
+	assert.Len(t, m.AsMapOfRanges(), 3)
+
+	length, err := granges.MeasureWith(byTime.Closed(day(1), day(3)), time.Time.Sub)
+	assert.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, length)
+
+	r, err := granges.IntersectAll([]granges.Range[time.Time]{byTime.Closed(day(1), day(5)), byTime.AtLeast(day(3))})
+	assert.NoError(t, err)
+	assert.True(t, r.Equal(byTime.Closed(day(3), day(5))))
+
+	days := []time.Time{day(0), day(2), day(4), day(6)}
+	assert.Equal(t, []time.Time{day(2), day(4)}, granges.Retain(days, byTime.Closed(day(1), day(5))))
+	lo, hi := granges.SubsliceWithin(days, byTime.OpenClosed(day(2), day(6)))
+	assert.Equal(t, []time.Time{day(4), day(6)}, days[lo:hi])
+
+	ok, err = granges.TilesExactly([]granges.Range[time.Time]{
+		byTime.ClosedOpen(day(3), day(5)),
+		byTime.ClosedOpen(day(1), day(3)),
+	}, byTime.ClosedOpen(day(1), day(5)))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	enclosing, err := granges.EncloseAll([]time.Time{day(4), day(1), day(3)})
+	assert.NoError(t, err)
+	assert.True(t, enclosing.Equal(granges.Closed(day(1), day(4))))
+
+	type pair struct{ a, b int }
+	_, err = granges.EncloseAll([]pair{{1, 2}})
+	assert.Error(t, err)
+}
+
+func TestComparator_compare(t *testing.T) {
+	other := granges.NewComparator(time.Time.Compare)
+	a := byTime.Closed(day(1), day(5))
+	b := other.Closed(day(1), day(5))
+	natural := granges.Closed(day(1), day(5))
+
+	// equal bounds, but different orderings are never the same
+	for _, pair := range [][2]granges.Range[time.Time]{{a, b}, {a, natural}, {b, natural}} {
+		x, y := pair[0], pair[1]
+		assert.False(t, x.Equal(y))
+		assert.NotZero(t, granges.Compare(x, y))
+		assert.Equal(t, granges.Compare(x, y), -granges.Compare(y, x))
+		assert.NotZero(t, granges.CompareLowerBound(x, y))
+		assert.NotZero(t, granges.CompareUpperBound(x, y))
+	}
+
+	// ranges are grouped by ordering: natural first, then by comparator
+	ranges := []granges.Range[time.Time]{
+		other.Closed(day(0), day(1)),
+		byTime.Closed(day(3), day(4)),
+		granges.Invalid[time.Time](),
+		granges.Closed(day(7), day(8)),
+		byTime.Closed(day(1), day(2)),
+		granges.Closed(day(2), day(3)),
+	}
+	granges.SortRanges(ranges)
+	assert.True(t, ranges[0].Equal(granges.Closed(day(2), day(3))))
+	assert.True(t, ranges[1].Equal(granges.Closed(day(7), day(8))))
+	assert.True(t, ranges[2].Equal(byTime.Closed(day(1), day(2))))
+	assert.True(t, ranges[3].Equal(byTime.Closed(day(3), day(4))))
+	assert.True(t, ranges[4].Equal(other.Closed(day(0), day(1))))
+	assert.True(t, ranges[5].IsInvalid())
+}
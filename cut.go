@@ -17,25 +17,67 @@ const (
 // can be done below a certain value, above a certain value, below all values
 // or above all values. With this object defined in this way, an interval can
 // always be represented by a pair of Cut instances.
-type Cut[C Comparable] struct {
+//
+// A cut carries the ordering its endpoint is compared with, which is the
+// natural ordering of C for cuts created by the functions below, or the
+// ordering of a Comparator. Types with no natural ordering, neither Comparable
+// nor Comparer, have no cuts at a value but those of ranges built by a
+// Comparator: NewBelowValue and NewAboveValue panic for such types, and
+// NewBelowValueE and NewAboveValueE return an error.
+type Cut[C any] struct {
 	cutType  CutType
 	endpoint C
+	order    *ordering[C]
 }
 
-func NewBelowAll[C Comparable]() Cut[C] {
-	return Cut[C]{cutType: BelowAll}
+func NewBelowAll[C any]() Cut[C] {
+	below, _ := naturalBelowAll[C]()
+	return below
 }
 
-func NewAboveAll[C Comparable]() Cut[C] {
-	return Cut[C]{cutType: AboveAll}
+func NewAboveAll[C any]() Cut[C] {
+	below, _ := naturalBelowAll[C]()
+	return below.aboveAll()
 }
 
-func NewBelowValue[C Comparable](value C) Cut[C] {
-	return Cut[C]{cutType: BelowValue, endpoint: value}
+// NewBelowValue returns the cut below value. It panics if C has no natural
+// ordering; see NewBelowValueE.
+func NewBelowValue[C any](value C) Cut[C] {
+	c, err := NewBelowValueE(value)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewBelowValueE returns the cut below value. An error will be returned if C
+// has no natural ordering, being neither Comparable nor Comparer.
+func NewBelowValueE[C any](value C) (Cut[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Cut[C]{}, err
+	}
+	return Cut[C]{cutType: BelowValue, endpoint: value, order: order}, nil
+}
+
+// NewAboveValue returns the cut above value. It panics if C has no natural
+// ordering; see NewAboveValueE.
+func NewAboveValue[C any](value C) Cut[C] {
+	c, err := NewAboveValueE(value)
+	if err != nil {
+		panic(err)
+	}
+	return c
 }
 
-func NewAboveValue[C Comparable](value C) Cut[C] {
-	return Cut[C]{cutType: AboveValue, endpoint: value}
+// NewAboveValueE returns the cut above value. An error will be returned if C
+// has no natural ordering, being neither Comparable nor Comparer.
+func NewAboveValueE[C any](value C) (Cut[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Cut[C]{}, err
+	}
+	return Cut[C]{cutType: AboveValue, endpoint: value, order: order}, nil
 }
 
 func (c Cut[C]) Endpoint() (endpoint C, err error) {
@@ -53,10 +95,10 @@ func (c Cut[C]) IsLessThan(value C) bool {
 		return false
 	case BelowValue:
 		// if endpoint <= value, cut is on the left side or same of value
-		return c.endpoint <= value
+		return c.orderWith(nil).compare(c.endpoint, value) <= 0
 	case AboveValue:
 		// if endpoint < value, cut is on the left side of value
-		return c.endpoint < value
+		return c.orderWith(nil).compare(c.endpoint, value) < 0
 	default:
 		return false
 	}
@@ -132,11 +174,8 @@ func (c Cut[C]) Compare(other Cut[C]) int {
 	}
 
 	// compare endpoint
-	if c.endpoint < other.endpoint {
-		return -1
-	}
-	if c.endpoint > other.endpoint {
-		return 1
+	if n := c.orderWith(other.order).compare(c.endpoint, other.endpoint); n != 0 {
+		return n
 	}
 
 	// compare cut type in same endpoint
@@ -153,3 +192,93 @@ func (c Cut[C]) Compare(other Cut[C]) int {
 func (c Cut[C]) Equal(other Cut[C]) bool {
 	return c.Compare(other) == 0
 }
+
+// withEndpoint returns a cut of the same type cutting the number line at
+// endpoint instead. Unbounded cuts are returned unchanged.
+func (c Cut[C]) withEndpoint(endpoint C) Cut[C] {
+	if c.cutType == BelowAll || c.cutType == AboveAll {
+		return c
+	}
+	return Cut[C]{cutType: c.cutType, endpoint: endpoint, order: c.order}
+}
+
+// orderWith returns the ordering to compare the endpoint of this cut by: the
+// one it carries, or else other, or else fallbackOrder for cuts carrying none
+// such as the zero Cut.
+func (c Cut[C]) orderWith(other *ordering[C]) *ordering[C] {
+	if c.order != nil {
+		return c.order
+	}
+	if other != nil {
+		return other
+	}
+	return fallbackOrder[C]()
+}
+
+// fallbackOrder returns the natural ordering of C, or else an ordering
+// holding every value equal to every other.
+func fallbackOrder[C any]() *ordering[C] {
+	if order, ok := naturalOrderOf[C](); ok {
+		return order
+	}
+	return &ordering[C]{compare: compareNothing[C]}
+}
+
+// belowAll returns the cut below all values with the ordering of this cut.
+func (c Cut[C]) belowAll() Cut[C] {
+	return Cut[C]{cutType: BelowAll, order: c.order}
+}
+
+// aboveAll returns the cut above all values with the ordering of this cut.
+func (c Cut[C]) aboveAll() Cut[C] {
+	return Cut[C]{cutType: AboveAll, order: c.order}
+}
+
+// belowValue returns the cut below value with the ordering of this cut.
+func (c Cut[C]) belowValue(value C) Cut[C] {
+	return Cut[C]{cutType: BelowValue, endpoint: value, order: c.order}
+}
+
+// aboveValue returns the cut above value with the ordering of this cut.
+func (c Cut[C]) aboveValue(value C) Cut[C] {
+	return Cut[C]{cutType: AboveValue, endpoint: value, order: c.order}
+}
+
+// mirrored returns the cut obtained by reflecting this cut about some origin,
+// landing at endpoint: the side of the value it is cut on is swapped, so that
+// a closed lower bound becomes a closed upper bound, and the cuts below and
+// above all values are swapped as well.
+func (c Cut[C]) mirrored(endpoint C) Cut[C] {
+	switch c.cutType {
+	case BelowAll:
+		return c.aboveAll()
+	case AboveAll:
+		return c.belowAll()
+	case BelowValue:
+		return c.aboveValue(endpoint)
+	default:
+		return c.belowValue(endpoint)
+	}
+}
+
+// canonical returns the cut below the least value of domain above this cut,
+// so that cuts between the same two adjacent values of domain are canonical
+// to the same cut. The cut below all values is canonical to the cut below the
+// minimum value of domain if there is one, and the cut above the maximum
+// value of domain is canonical to the cut above all values.
+func (c Cut[C]) canonical(domain DiscreteDomain[C]) Cut[C] {
+	switch c.cutType {
+	case BelowAll:
+		if minValue, ok := domain.MinValue(); ok {
+			return c.belowValue(minValue)
+		}
+		return c
+	case AboveValue:
+		if next, ok := domain.Next(c.endpoint); ok {
+			return c.belowValue(next)
+		}
+		return c.aboveAll()
+	default:
+		return c
+	}
+}
@@ -98,3 +98,31 @@ func testCompareToAndEquals(t *testing.T, rs []Cut[int]) {
 		}
 	}
 }
+
+func TestCut_GobDecode_invalid(t *testing.T) {
+	data, err := Cut[int]{cutType: 7, endpoint: 1}.GobEncode()
+	assert.NoError(t, err)
+	cut := NewBelowValue(3)
+	assert.Error(t, cut.GobDecode(data))
+	assert.Equal(t, NewBelowValue(3), cut)
+
+	data, err = Range[int]{lowerBound: NewBelowValue(5), upperBound: NewAboveValue(1)}.GobEncode()
+	assert.NoError(t, err)
+	r := Closed(7, 9)
+	assert.Error(t, r.GobDecode(data))
+	assert.True(t, Closed(7, 9).Equal(r))
+}
+
+func TestCut_GobDecode_unordered(t *testing.T) {
+	type point struct{ X, Y int }
+	data, err := Cut[point]{cutType: BelowValue, endpoint: point{1, 2}}.GobEncode()
+	assert.NoError(t, err)
+	var cut Cut[point]
+	assert.Error(t, cut.GobDecode(data))
+
+	// unbounded cuts have no endpoint to compare
+	data, err = Cut[point]{cutType: AboveAll}.GobEncode()
+	assert.NoError(t, err)
+	assert.NoError(t, cut.GobDecode(data))
+	assert.False(t, cut.IsLessThan(point{1, 2}))
+}
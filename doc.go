@@ -2,7 +2,8 @@
 Package granges implements a mathematical interval operation tool.
 
 A range (or "interval") defines the boundaries around a contiguous span of
-values of some Comparable type; for example, "integers from 1 to 100 inclusive".
+values of some Comparable or Comparer type; for example, "integers from 1 to
+100 inclusive". Values of other types are ordered by a Comparator.
 
 # Types of ranges
 
@@ -0,0 +1,118 @@
+package granges
+
+import "math"
+
+// DiscreteDomain describes a type whose values are discrete, such as
+// integers: every value has at most one next greater value and one previous
+// smaller value, with nothing between them. A discrete domain is needed to
+// reason about the values a range contains, rather than about its bounds;
+// for example, it tells that the integer ranges (1..4) and [2..3] hold the
+// same values, see Range.Canonical.
+type DiscreteDomain[C any] interface {
+	// Next returns the least value greater than value, or false if value is
+	// the maximum value of the domain.
+	Next(value C) (C, bool)
+
+	// Previous returns the greatest value less than value, or false if value
+	// is the minimum value of the domain.
+	Previous(value C) (C, bool)
+
+	// MinValue returns the minimum value of the domain, or false if the
+	// domain has none.
+	MinValue() (C, bool)
+
+	// MaxValue returns the maximum value of the domain, or false if the
+	// domain has none.
+	MaxValue() (C, bool)
+
+	// Distance returns the number of steps of Next from a to b, which is
+	// negative if b is less than a. It saturates at the limits of int64 if
+	// the distance does not fit.
+	Distance(a, b C) int64
+}
+
+// IntegerDomain returns the discrete domain of the values of an integer type
+// T, whose minimum and maximum values are those of T. For example, it
+// enumerates [3..7) as 3, 4, 5 and 6.
+func IntegerDomain[T Integer]() DiscreteDomain[T] {
+	minValue, maxValue := integerLimits[T]()
+	return integerDomain[T]{minValue: minValue, maxValue: maxValue}
+}
+
+type integerDomain[T Integer] struct {
+	minValue, maxValue T
+}
+
+func (d integerDomain[T]) Next(value T) (T, bool) {
+	if value == d.maxValue {
+		return value, false
+	}
+	return value + 1, true
+}
+
+func (d integerDomain[T]) Previous(value T) (T, bool) {
+	if value == d.minValue {
+		return value, false
+	}
+	return value - 1, true
+}
+
+func (d integerDomain[T]) MinValue() (T, bool) {
+	return d.minValue, true
+}
+
+func (d integerDomain[T]) MaxValue() (T, bool) {
+	return d.maxValue, true
+}
+
+func (d integerDomain[T]) Distance(a, b T) int64 {
+	if b >= a {
+		return int64(min(d.gap(a, b), math.MaxInt64))
+	}
+	// the gap may be 2^63, whose negation is still representable
+	gap := d.gap(b, a)
+	if gap > 1<<63 {
+		return math.MinInt64
+	}
+	return int64(-gap)
+}
+
+// offset returns value + steps for steps >= 0, or false if it is greater than
+// the maximum value. It implements offsetter.
+func (d integerDomain[T]) offset(value T, steps int64) (T, bool) {
+	if uint64(steps) > d.gap(value, d.maxValue) {
+		return value, false
+	}
+	// wrapping around is fine for small types, as the sum fits in T
+	return value + T(steps), true
+}
+
+// gap returns hi - lo for lo <= hi, which always fits in an uint64.
+func (d integerDomain[T]) gap(lo, hi T) uint64 {
+	if d.minValue < 0 {
+		return uint64(int64(hi)) - uint64(int64(lo))
+	}
+	return uint64(hi) - uint64(lo)
+}
+
+// offsetter is implemented by discrete domains able to step over many values
+// at once, rather than calling Next for each of them.
+type offsetter[C any] interface {
+	offset(value C, steps int64) (C, bool)
+}
+
+// advance returns the value steps calls of Next after value in domain, where
+// steps >= 0, or false if domain has no such value.
+func advance[C any](domain DiscreteDomain[C], value C, steps int64) (C, bool) {
+	if o, ok := domain.(offsetter[C]); ok {
+		return o.offset(value, steps)
+	}
+	for ; steps > 0; steps-- {
+		next, ok := domain.Next(value)
+		if !ok {
+			return value, false
+		}
+		value = next
+	}
+	return value, true
+}
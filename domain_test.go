@@ -0,0 +1,80 @@
+package granges_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestIntegerDomain(t *testing.T) {
+	domain := granges.IntegerDomain[int]()
+
+	next, ok := domain.Next(3)
+	assert.True(t, ok)
+	assert.Equal(t, 4, next)
+	_, ok = domain.Next(math.MaxInt)
+	assert.False(t, ok)
+
+	previous, ok := domain.Previous(3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, previous)
+	_, ok = domain.Previous(math.MinInt)
+	assert.False(t, ok)
+
+	minValue, ok := domain.MinValue()
+	assert.True(t, ok)
+	assert.Equal(t, math.MinInt, minValue)
+	maxValue, ok := domain.MaxValue()
+	assert.True(t, ok)
+	assert.Equal(t, math.MaxInt, maxValue)
+
+	var values []int
+	for v, ok := 3, true; ok && granges.ClosedOpen(3, 7).Contains(v); v, ok = domain.Next(v) {
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{3, 4, 5, 6}, values)
+}
+
+func TestIntegerDomain_limits(t *testing.T) {
+	u8 := granges.IntegerDomain[uint8]()
+	minU8, _ := u8.MinValue()
+	maxU8, _ := u8.MaxValue()
+	assert.Equal(t, uint8(0), minU8)
+	assert.Equal(t, uint8(math.MaxUint8), maxU8)
+	_, ok := u8.Previous(0)
+	assert.False(t, ok)
+	_, ok = u8.Next(math.MaxUint8)
+	assert.False(t, ok)
+
+	type level int16
+	levels := granges.IntegerDomain[level]()
+	minLevel, _ := levels.MinValue()
+	maxLevel, _ := levels.MaxValue()
+	assert.Equal(t, level(math.MinInt16), minLevel)
+	assert.Equal(t, level(math.MaxInt16), maxLevel)
+}
+
+func TestIntegerDomain_Distance(t *testing.T) {
+	ints := granges.IntegerDomain[int64]()
+	assert.Equal(t, int64(4), ints.Distance(3, 7))
+	assert.Equal(t, int64(-4), ints.Distance(7, 3))
+	assert.Equal(t, int64(0), ints.Distance(7, 7))
+	assert.Equal(t, int64(math.MaxInt64), ints.Distance(-1, math.MaxInt64))
+	assert.Equal(t, int64(math.MaxInt64), ints.Distance(math.MinInt64, math.MaxInt64))
+	assert.Equal(t, int64(math.MinInt64), ints.Distance(0, math.MinInt64))
+	assert.Equal(t, int64(math.MinInt64), ints.Distance(math.MaxInt64, math.MinInt64))
+
+	i8 := granges.IntegerDomain[int8]()
+	assert.Equal(t, int64(255), i8.Distance(math.MinInt8, math.MaxInt8))
+	assert.Equal(t, int64(-255), i8.Distance(math.MaxInt8, math.MinInt8))
+
+	u64 := granges.IntegerDomain[uint64]()
+	assert.Equal(t, int64(math.MaxInt64), u64.Distance(0, math.MaxInt64))
+	assert.Equal(t, int64(math.MaxInt64), u64.Distance(0, math.MaxUint64))
+	assert.Equal(t, int64(math.MinInt64), u64.Distance(1<<63, 0))
+	assert.Equal(t, int64(math.MinInt64), u64.Distance(math.MaxUint64, 0))
+	assert.Equal(t, int64(-2), u64.Distance(5, 3))
+}
@@ -0,0 +1,398 @@
+package granges
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+const (
+	jsonClosed    = "closed"
+	jsonOpen      = "open"
+	jsonUnbounded = "unbounded"
+)
+
+// jsonRange is the JSON representation of a range, see Range.MarshalJSON.
+type jsonRange[C any] struct {
+	Lower jsonBound[C] `json:"lower"`
+	Upper jsonBound[C] `json:"upper"`
+}
+
+// jsonBound is the JSON representation of one side of a range.
+type jsonBound[C any] struct {
+	Value *C     `json:"value,omitempty"`
+	Type  string `json:"type"`
+}
+
+func newJSONBound[C any](endpoint *C, inclusive bool) jsonBound[C] {
+	switch {
+	case endpoint == nil:
+		return jsonBound[C]{Type: jsonUnbounded}
+	case inclusive:
+		return jsonBound[C]{Value: endpoint, Type: jsonClosed}
+	default:
+		return jsonBound[C]{Value: endpoint, Type: jsonOpen}
+	}
+}
+
+// endpoint returns the endpoint of this side and whether it is inclusive, or
+// nil for an unbounded side.
+func (b jsonBound[C]) endpoint() (*C, bool, error) {
+	switch b.Type {
+	case jsonUnbounded:
+		if b.Value != nil {
+			return nil, false, fmt.Errorf("unbounded side cannot have a value")
+		}
+		return nil, false, nil
+	case jsonClosed, jsonOpen:
+		if b.Value == nil {
+			return nil, false, fmt.Errorf("%s side must have a value", b.Type)
+		}
+		return b.Value, b.Type == jsonClosed, nil
+	default:
+		return nil, false, fmt.Errorf("%w: %q", ErrWrongBoundType, b.Type)
+	}
+}
+
+// MarshalJSON encodes this range as an object holding its lower and upper
+// sides, each with the endpoint as "value" and the bound type as "type",
+// either "closed" or "open". An unbounded side has no value and the type
+// "unbounded". For example, [1..5) is encoded as
+//
+//	{"lower":{"value":1,"type":"closed"},"upper":{"value":5,"type":"open"}}
+//
+// and [1..+∞) as
+//
+//	{"lower":{"value":1,"type":"closed"},"upper":{"type":"unbounded"}}
+//
+// An error will be returned if this range is invalid.
+func (r Range[C]) MarshalJSON() ([]byte, error) {
+	b, err := ToBounds(r)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonRange[C]{
+		Lower: newJSONBound(b.Lower, b.LowerInclusive),
+		Upper: newJSONBound(b.Upper, b.UpperInclusive),
+	})
+}
+
+// UnmarshalJSON decodes a range encoded by MarshalJSON, with the same
+// validation as NewE. An error will be returned, leaving this range
+// unchanged, if the lower endpoint is greater than the upper one, if they are
+// equal and both open, if a side has an unknown type, or if a bounded side
+// has no value. Like other decoders, it does nothing for a JSON null.
+func (r *Range[C]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var jr jsonRange[C]
+	if err := json.Unmarshal(data, &jr); err != nil {
+		return err
+	}
+	var b Bounds[C]
+	var err error
+	if b.Lower, b.LowerInclusive, err = jr.Lower.endpoint(); err != nil {
+		return fmt.Errorf("lower bound: %w", err)
+	}
+	if b.Upper, b.UpperInclusive, err = jr.Upper.endpoint(); err != nil {
+		return fmt.Errorf("upper bound: %w", err)
+	}
+	order, err := r.decodingOrder()
+	if err != nil {
+		return err
+	}
+	decoded, err := fromBounds(b, order)
+	if err != nil {
+		return err
+	}
+	*r = decoded
+	return nil
+}
+
+// MarshalText encodes this range in the notation of String, such as [4..8)
+// or (-∞..5). Only ranges of integer and float types can be encoded as text,
+// an error will be returned for other types or if this range is invalid.
+func (r Range[C]) MarshalText() ([]byte, error) {
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot marshal invalid range")
+	}
+	if _, err := parseNumber[C]("0"); err != nil {
+		return nil, err
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a range of an integer or float type encoded by
+// MarshalText. Unbounded sides may also be written as -inf and +inf for
+// integer types. For float types, -∞ and +∞ are the only spellings of
+// unbounded sides, while -Inf and +Inf are infinite endpoints, so that
+// Closed(math.Inf(-1), 0) encodes as [-Inf..0] and decodes back to itself.
+//
+// An error will be returned, leaving this range unchanged, if text is
+// malformed, if an endpoint does not fit in C, or if the range would be
+// invalid, such as (4..4).
+func (r *Range[C]) UnmarshalText(text []byte) error {
+	order, err := r.decodingOrder()
+	if err != nil {
+		return err
+	}
+	decoded, err := parse(string(text), parseNumber[C], order)
+	if err != nil {
+		return err
+	}
+	*r = decoded
+	return nil
+}
+
+// parseNumber parses s as a value of C, whose underlying type must be an
+// integer or float type.
+func parseNumber[C any](s string) (C, error) {
+	var value C
+	v := reflect.ValueOf(&value).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return value, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return value, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return value, err
+		}
+		v.SetFloat(f)
+	default:
+		return value, fmt.Errorf("text encoding is not supported for %s ranges", v.Type())
+	}
+	return value, nil
+}
+
+// gobCut is the gob representation of a cut.
+type gobCut[C any] struct {
+	Type     CutType
+	Endpoint C
+}
+
+// GobEncode encodes the type and the endpoint of this cut with encoding/gob.
+func (c Cut[C]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobCut[C]{Type: c.cutType, Endpoint: c.endpoint}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes a cut encoded by GobEncode. An error will be returned,
+// leaving this cut unchanged, if the encoded cut type is unknown, or if it is
+// a cut at a value of a type with no natural ordering and this cut was not
+// ordered by a Comparator.
+func (c *Cut[C]) GobDecode(data []byte) error {
+	var gc gobCut[C]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&gc); err != nil {
+		return err
+	}
+	if gc.Type < BelowAll || gc.Type > AboveValue {
+		return fmt.Errorf("unknown cut type %d", gc.Type)
+	}
+	order := c.order
+	if order == nil {
+		var err error
+		order, err = orderOf[C]()
+		if err != nil && (gc.Type == BelowValue || gc.Type == AboveValue) {
+			return err
+		}
+	}
+	*c = Cut[C]{cutType: gc.Type, endpoint: gc.Endpoint, order: order}
+	return nil
+}
+
+// gobRange is the gob representation of a range.
+type gobRange[C any] struct {
+	Lower, Upper Cut[C]
+	Invalid      bool
+}
+
+// GobEncode encodes the cuts of this range, or the fact that it is invalid,
+// with encoding/gob, so that ranges can be sent with net/rpc.
+func (r Range[C]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	gr := gobRange[C]{Lower: r.lowerBound, Upper: r.upperBound, Invalid: r.invalid}
+	if err := gob.NewEncoder(&buf).Encode(gr); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes a range encoded by GobEncode, with the same validation as
+// NewE for valid ranges. An error will be returned, leaving this range
+// unchanged, if data is malformed or if it holds cuts not forming a range.
+func (r *Range[C]) GobDecode(data []byte) error {
+	var gr gobRange[C]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&gr); err != nil {
+		return err
+	}
+	if gr.Invalid {
+		*r = Invalid[C]()
+		return nil
+	}
+	order, err := r.decodingOrder()
+	if err != nil {
+		return err
+	}
+	gr.Lower.order, gr.Upper.order = order, order
+	decoded, err := create(gr.Lower, gr.Upper)
+	if err != nil {
+		return err
+	}
+	*r = decoded
+	return nil
+}
+
+// binaryInvalid is the cut type byte encoding both sides of invalid ranges in
+// the binary format.
+const binaryInvalid = 0xff
+
+// MarshalBinary encodes this range of an integer or float type in a compact
+// layout: one byte for the type of the lower cut and one for the type of the
+// upper cut, followed by the big-endian endpoints of the bounded sides only.
+// Endpoints take the size of C, except for int, uint and uintptr, which
+// always take 8 bytes so that the encoding does not depend on the platform.
+// Invalid ranges are encoded as two 0xff bytes.
+//
+// An error will be returned if C is neither an integer nor a float type.
+func (r Range[C]) MarshalBinary() ([]byte, error) {
+	size, err := binaryEndpointSize[C]()
+	if err != nil {
+		return nil, err
+	}
+	if r.IsInvalid() {
+		return []byte{binaryInvalid, binaryInvalid}, nil
+	}
+	data := make([]byte, 2, 2+2*size)
+	data[0], data[1] = byte(r.lowerBound.cutType), byte(r.upperBound.cutType)
+	for _, cut := range []Cut[C]{r.lowerBound, r.upperBound} {
+		if cut.cutType == BelowValue || cut.cutType == AboveValue {
+			data = appendBinaryEndpoint(data, cut.endpoint)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a range encoded by MarshalBinary, with the same
+// validation as NewE for valid ranges. An error will be returned, leaving this
+// range unchanged, if data is truncated or too long, if it holds an unknown
+// cut type, or if its cuts do not form a range.
+func (r *Range[C]) UnmarshalBinary(data []byte) error {
+	size, err := binaryEndpointSize[C]()
+	if err != nil {
+		return err
+	}
+	if len(data) < 2 {
+		return fmt.Errorf("truncated range: %d bytes", len(data))
+	}
+	if data[0] == binaryInvalid && data[1] == binaryInvalid && len(data) == 2 {
+		*r = Invalid[C]()
+		return nil
+	}
+	order, err := r.decodingOrder()
+	if err != nil {
+		return err
+	}
+	cuts := [2]Cut[C]{{order: order}, {order: order}}
+	rest := data[2:]
+	for i, cutType := range data[:2] {
+		if CutType(cutType) > AboveValue {
+			return fmt.Errorf("unknown cut type %d", cutType)
+		}
+		cuts[i].cutType = CutType(cutType)
+		if cuts[i].cutType == BelowValue || cuts[i].cutType == AboveValue {
+			if len(rest) < size {
+				return fmt.Errorf("truncated range: %d bytes", len(data))
+			}
+			cuts[i].endpoint = readBinaryEndpoint[C](rest[:size])
+			rest = rest[size:]
+		}
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("%d trailing bytes after range", len(rest))
+	}
+	decoded, err := create(cuts[0], cuts[1])
+	if err != nil {
+		return err
+	}
+	*r = decoded
+	return nil
+}
+
+// binaryEndpointSize returns the size of the endpoints of C in the binary
+// format.
+func binaryEndpointSize[C any]() (int, error) {
+	var zero C
+	t := reflect.TypeOf(zero)
+	switch t.Kind() {
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return 8, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return t.Bits() / 8, nil
+	default:
+		return 0, fmt.Errorf("binary encoding is not supported for %s ranges", t)
+	}
+}
+
+// appendBinaryEndpoint appends endpoint to data in the binary format.
+func appendBinaryEndpoint[C any](data []byte, endpoint C) []byte {
+	v := reflect.ValueOf(endpoint)
+	var bits uint64
+	switch {
+	case v.CanInt():
+		bits = uint64(v.Int())
+	case v.CanUint():
+		bits = v.Uint()
+	case v.Kind() == reflect.Float32:
+		bits = uint64(math.Float32bits(float32(v.Float())))
+	default:
+		bits = math.Float64bits(v.Float())
+	}
+	size, _ := binaryEndpointSize[C]()
+	for i := size - 1; i >= 0; i-- {
+		data = append(data, byte(bits>>(8*i)))
+	}
+	return data
+}
+
+// readBinaryEndpoint reads an endpoint of C from data in the binary format.
+func readBinaryEndpoint[C any](data []byte) C {
+	var endpoint C
+	v := reflect.ValueOf(&endpoint).Elem()
+	var bits uint64
+	for _, b := range data {
+		bits = bits<<8 | uint64(b)
+	}
+	switch {
+	case v.CanInt():
+		// sign-extend the endpoint to 64 bits
+		shift := 64 - 8*len(data)
+		v.SetInt(int64(bits<<shift) >> shift)
+	case v.CanUint():
+		v.SetUint(bits)
+	case v.Kind() == reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(bits))))
+	default:
+		v.SetFloat(math.Float64frombits(bits))
+	}
+	return endpoint
+}
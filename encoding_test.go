@@ -0,0 +1,356 @@
+package granges_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestRange_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want string
+	}{
+		{R: granges.ClosedOpen(1, 5), Want: `{"lower":{"value":1,"type":"closed"},"upper":{"value":5,"type":"open"}}`},
+		{R: granges.OpenClosed(0, 5), Want: `{"lower":{"value":0,"type":"open"},"upper":{"value":5,"type":"closed"}}`},
+		{R: granges.AtLeast(1), Want: `{"lower":{"value":1,"type":"closed"},"upper":{"type":"unbounded"}}`},
+		{R: granges.LessThan(-1), Want: `{"lower":{"type":"unbounded"},"upper":{"value":-1,"type":"open"}}`},
+		{R: granges.All[int](), Want: `{"lower":{"type":"unbounded"},"upper":{"type":"unbounded"}}`},
+		{R: granges.ClosedOpen(4, 4), Want: `{"lower":{"value":4,"type":"closed"},"upper":{"value":4,"type":"open"}}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.R)
+		assert.NoError(t, err)
+		assert.JSONEq(t, tt.Want, string(data), "Marshal(%v)", tt.R)
+
+		var decoded granges.Range[int]
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, tt.R.Equal(decoded), "Unmarshal(%s) = %v, want %v", data, decoded, tt.R)
+	}
+
+	_, err := json.Marshal(granges.Invalid[int]())
+	assert.Error(t, err)
+}
+
+func TestRange_MarshalJSON_roundTrip(t *testing.T) {
+	type config struct {
+		Hours   granges.Range[float64] `json:"hours"`
+		Letters granges.Range[string]  `json:"letters"`
+	}
+	in := config{Hours: granges.Open(8.5, 17.0), Letters: granges.AtMost("m")}
+
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+
+	var out config
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.True(t, in.Hours.Equal(out.Hours))
+	assert.True(t, in.Letters.Equal(out.Letters))
+}
+
+func TestRange_UnmarshalJSON_errors(t *testing.T) {
+	tests := []string{
+		`{"lower":{"value":5,"type":"closed"},"upper":{"value":1,"type":"closed"}}`,
+		`{"lower":{"value":1,"type":"open"},"upper":{"value":1,"type":"open"}}`,
+		`{"lower":{"value":1,"type":"half"},"upper":{"type":"unbounded"}}`,
+		`{"lower":{"type":"closed"},"upper":{"type":"unbounded"}}`,
+		`{"lower":{"value":1,"type":"unbounded"},"upper":{"type":"unbounded"}}`,
+		`{"lower":{"value":"a","type":"closed"},"upper":{"type":"unbounded"}}`,
+		`{}`,
+		`[1, 5]`,
+	}
+
+	for _, data := range tests {
+		r := granges.Closed(7, 9)
+		assert.Error(t, json.Unmarshal([]byte(data), &r), "Unmarshal(%s)", data)
+		assert.True(t, granges.Closed(7, 9).Equal(r), "Unmarshal(%s) changed the range to %v", data, r)
+	}
+
+	r := granges.Closed(7, 9)
+	err := json.Unmarshal([]byte(`{"lower":{"value":1,"type":"half"},"upper":{"type":"unbounded"}}`), &r)
+	assert.ErrorIs(t, err, granges.ErrWrongBoundType)
+
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &r))
+	assert.True(t, granges.Closed(7, 9).Equal(r))
+}
+
+func TestRange_MarshalText(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want string
+	}{
+		{R: granges.Open(4, 8), Want: "(4..8)"},
+		{R: granges.Closed(4, 8), Want: "[4..8]"},
+		{R: granges.ClosedOpen(-4, 8), Want: "[-4..8)"},
+		{R: granges.OpenClosed(4, 8), Want: "(4..8]"},
+		{R: granges.LessThan(5), Want: "(-∞..5)"},
+		{R: granges.AtMost(5), Want: "(-∞..5]"},
+		{R: granges.GreaterThan(5), Want: "(5..+∞)"},
+		{R: granges.AtLeast(5), Want: "[5..+∞)"},
+		{R: granges.All[int](), Want: "(-∞..+∞)"},
+		{R: granges.ClosedOpen(4, 4), Want: "[4..4)"},
+	}
+
+	for _, tt := range tests {
+		text, err := tt.R.MarshalText()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, string(text))
+
+		var decoded granges.Range[int]
+		assert.NoError(t, decoded.UnmarshalText(text))
+		assert.True(t, tt.R.Equal(decoded), "UnmarshalText(%s) = %v, want %v", text, decoded, tt.R)
+	}
+
+	_, err := granges.Invalid[int]().MarshalText()
+	assert.Error(t, err)
+	_, err = granges.Closed("a", "b").MarshalText()
+	assert.Error(t, err)
+}
+
+func TestRange_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		Text string
+		Want granges.Range[float64]
+	}{
+		{Text: "[0.5..2.25)", Want: granges.ClosedOpen(0.5, 2.25)},
+		{Text: "  ( -1.5 .. 1e3 ]  ", Want: granges.OpenClosed(-1.5, 1000)},
+		{Text: "(-∞..5]", Want: granges.AtMost(5.0)},
+		// -inf and +inf are the infinite floats, not unbounded sides
+		{Text: "(-inf..5]", Want: granges.OpenClosed(math.Inf(-1), 5)},
+		{Text: "(5..+INF)", Want: granges.Open(5, math.Inf(1))},
+		{Text: "(-∞..+inf)", Want: granges.LessThan(math.Inf(1))},
+	}
+
+	for _, tt := range tests {
+		var r granges.Range[float64]
+		assert.NoError(t, r.UnmarshalText([]byte(tt.Text)), "UnmarshalText(%s)", tt.Text)
+		assert.True(t, tt.Want.Equal(r), "UnmarshalText(%s) = %v, want %v", tt.Text, r, tt.Want)
+	}
+
+	// unbounded sides of integer ranges may be spelled inf
+	for text, want := range map[string]granges.Range[int]{
+		"(-inf..5]":  granges.AtMost(5),
+		"(5..+INF)":  granges.GreaterThan(5),
+		"(-∞..+inf)": granges.All[int](),
+	} {
+		var r granges.Range[int]
+		assert.NoError(t, r.UnmarshalText([]byte(text)), "UnmarshalText(%s)", text)
+		assert.True(t, want.Equal(r), "UnmarshalText(%s) = %v, want %v", text, r, want)
+	}
+
+	var r8 granges.Range[uint8]
+	assert.NoError(t, r8.UnmarshalText([]byte("[0..255]")))
+	assert.True(t, granges.Closed[uint8](0, 255).Equal(r8))
+
+	for _, text := range []string{
+		"(4..4)", "[5..4]", "[-∞..5]", "(5..+∞]", "(+∞..5)", "(5..-∞)", "4..8", "[4,8]", "{4..8}", "[a..8]", "[4..8.5]", "[..]", "[", "",
+	} {
+		r := granges.Closed(7, 9)
+		assert.Error(t, r.UnmarshalText([]byte(text)), "UnmarshalText(%s)", text)
+		assert.True(t, granges.Closed(7, 9).Equal(r), "UnmarshalText(%s) changed the range to %v", text, r)
+	}
+	assert.Error(t, r8.UnmarshalText([]byte("[0..256]")))
+	assert.Error(t, r8.UnmarshalText([]byte("[-1..2]")))
+
+	var s granges.Range[string]
+	assert.Error(t, s.UnmarshalText([]byte("[a..b]")))
+}
+
+func TestRange_MarshalText_infinities(t *testing.T) {
+	inf := math.Inf(1)
+	for _, r := range []granges.Range[float64]{
+		granges.Closed(-inf, 0),
+		granges.Open(-inf, 0),
+		granges.ClosedOpen(0, inf),
+		granges.OpenClosed(-inf, inf),
+		granges.Closed(-inf, inf),
+		granges.Singleton(inf),
+		granges.AtLeast(-inf),
+		granges.LessThan(inf),
+		granges.LessThan(0.0),
+	} {
+		text, err := r.MarshalText()
+		assert.NoError(t, err)
+
+		var decoded granges.Range[float64]
+		assert.NoError(t, decoded.UnmarshalText(text), "UnmarshalText(%s)", text)
+		assert.True(t, r.Equal(decoded), "UnmarshalText(%s) = %v, want %v", text, decoded, r)
+	}
+
+	text, err := granges.Closed(math.Inf(-1), 0).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "[-Inf..0]", string(text))
+}
+
+func TestRange_MarshalText_mapKeys(t *testing.T) {
+	in := map[granges.Range[int]]string{
+		granges.ClosedOpen(0, 10): "low",
+		granges.AtLeast(10):       "high",
+	}
+
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"[0..10)":"low","[10..+∞)":"high"}`, string(data))
+
+	var out map[granges.Range[int]]string
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestRange_GobEncode(t *testing.T) {
+	tests := []granges.Range[int]{
+		granges.Open(1, 5),
+		granges.Closed(-1, 5),
+		granges.ClosedOpen(1, 5),
+		granges.OpenClosed(1, 5),
+		granges.LessThan(5),
+		granges.AtMost(5),
+		granges.GreaterThan(0),
+		granges.AtLeast(0),
+		granges.All[int](),
+		granges.ClosedOpen(0, 0),
+	}
+
+	for _, r := range tests {
+		var buf bytes.Buffer
+		assert.NoError(t, gob.NewEncoder(&buf).Encode(r))
+
+		var decoded granges.Range[int]
+		assert.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+		assert.True(t, r.Equal(decoded), "gob round trip of %v = %v", r, decoded)
+		assert.False(t, decoded.IsInvalid())
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(granges.Invalid[int]()))
+	decoded := granges.Closed(7, 9)
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	assert.True(t, decoded.IsInvalid())
+
+	type payload struct {
+		Name  string
+		Hours granges.Range[float64]
+		Keys  []granges.Range[string]
+	}
+	in := payload{Name: "shift", Hours: granges.ClosedOpen(8.5, 17.0), Keys: []granges.Range[string]{granges.AtLeast("m"), granges.PrefixRange("user/")}}
+	buf.Reset()
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(in))
+	var out payload
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	assert.Equal(t, in.Name, out.Name)
+	assert.True(t, in.Hours.Equal(out.Hours))
+	if assert.Len(t, out.Keys, 2) {
+		assert.True(t, in.Keys[0].Equal(out.Keys[0]))
+		assert.True(t, in.Keys[1].Equal(out.Keys[1]))
+	}
+}
+
+func TestRange_GobDecode_errors(t *testing.T) {
+	r := granges.Closed(7, 9)
+	assert.Error(t, r.GobDecode([]byte("garbage")))
+	assert.True(t, granges.Closed(7, 9).Equal(r))
+
+	var cut granges.Cut[int]
+	assert.Error(t, cut.GobDecode([]byte("garbage")))
+
+	data, err := granges.AtLeast(5).GobEncode()
+	assert.NoError(t, err)
+	var wrongType granges.Range[string]
+	assert.Error(t, wrongType.GobDecode(data))
+}
+
+func TestRange_MarshalBinary(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int16]
+		Want []byte
+	}{
+		{R: granges.Open[int16](1, 5), Want: []byte{3, 2, 0, 1, 0, 5}},
+		{R: granges.Closed[int16](-1, 5), Want: []byte{2, 3, 0xff, 0xff, 0, 5}},
+		{R: granges.ClosedOpen[int16](1, 0x1234), Want: []byte{2, 2, 0, 1, 0x12, 0x34}},
+		{R: granges.OpenClosed[int16](1, 5), Want: []byte{3, 3, 0, 1, 0, 5}},
+		{R: granges.LessThan[int16](5), Want: []byte{0, 2, 0, 5}},
+		{R: granges.AtMost[int16](-32768), Want: []byte{0, 3, 0x80, 0}},
+		{R: granges.GreaterThan[int16](5), Want: []byte{3, 1, 0, 5}},
+		{R: granges.AtLeast[int16](5), Want: []byte{2, 1, 0, 5}},
+		{R: granges.All[int16](), Want: []byte{0, 1}},
+		{R: granges.ClosedOpen[int16](4, 4), Want: []byte{2, 2, 0, 4, 0, 4}},
+		{R: granges.Invalid[int16](), Want: []byte{0xff, 0xff}},
+	}
+
+	for _, tt := range tests {
+		data, err := tt.R.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, data, "%v.MarshalBinary()", tt.R)
+
+		var decoded granges.Range[int16]
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, tt.R.IsInvalid(), decoded.IsInvalid())
+		assert.True(t, tt.R.Equal(decoded), "UnmarshalBinary(%v) = %v, want %v", data, decoded, tt.R)
+	}
+}
+
+func TestRange_MarshalBinary_types(t *testing.T) {
+	assertRoundTrip := func(r interface {
+		MarshalBinary() ([]byte, error)
+	}, decoded interface{ UnmarshalBinary([]byte) error }, size int) {
+		data, err := r.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Len(t, data, size)
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+	}
+
+	var i granges.Range[int]
+	assertRoundTrip(granges.Closed(math.MinInt, math.MaxInt), &i, 18)
+	assert.True(t, granges.Closed(math.MinInt, math.MaxInt).Equal(i))
+
+	var u8 granges.Range[uint8]
+	assertRoundTrip(granges.ClosedOpen[uint8](0, 255), &u8, 4)
+	assert.True(t, granges.ClosedOpen[uint8](0, 255).Equal(u8))
+
+	var u64 granges.Range[uint64]
+	assertRoundTrip(granges.AtLeast[uint64](math.MaxUint64), &u64, 10)
+	assert.True(t, granges.AtLeast[uint64](math.MaxUint64).Equal(u64))
+
+	var f32 granges.Range[float32]
+	assertRoundTrip(granges.Open[float32](-1.5, 3.25), &f32, 10)
+	assert.True(t, granges.Open[float32](-1.5, 3.25).Equal(f32))
+
+	var f64 granges.Range[float64]
+	assertRoundTrip(granges.OpenClosed(-math.MaxFloat64, math.SmallestNonzeroFloat64), &f64, 18)
+	assert.True(t, granges.OpenClosed(-math.MaxFloat64, math.SmallestNonzeroFloat64).Equal(f64))
+
+	_, err := granges.Closed("a", "b").MarshalBinary()
+	assert.Error(t, err)
+	var s granges.Range[string]
+	assert.Error(t, s.UnmarshalBinary([]byte{0, 1}))
+}
+
+func TestRange_UnmarshalBinary_errors(t *testing.T) {
+	tests := [][]byte{
+		nil,
+		{2},
+		{2, 3},
+		{2, 3, 0, 1},
+		{2, 3, 0, 1, 0},
+		{2, 3, 0, 5, 0, 1},
+		{3, 2, 0, 5, 0, 5},
+		{0, 1, 0},
+		{4, 1},
+		{0, 0xff},
+		{0xff, 0xff, 0},
+		{1, 0},
+	}
+
+	for _, data := range tests {
+		r := granges.Closed[int16](7, 9)
+		assert.Error(t, r.UnmarshalBinary(data), "UnmarshalBinary(%v)", data)
+		assert.True(t, granges.Closed[int16](7, 9).Equal(r), "UnmarshalBinary(%v) changed the range to %v", data, r)
+	}
+}
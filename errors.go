@@ -6,4 +6,7 @@ var (
 	ErrRangeSideUnbounded = errors.New("range unbounded on this side")
 	ErrUnboundedCut       = errors.New("unbounded cut")
 	ErrWrongBoundType     = errors.New("unknown bound type")
+	ErrOverflow           = errors.New("arithmetic overflow")
+	ErrDivisionByZero     = errors.New("division by zero")
+	ErrComparatorMismatch = errors.New("ranges ordered by different comparators")
 )
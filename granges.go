@@ -1,12 +1,18 @@
 package granges
 
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
 // Open returns a range that contains all values strictly greater than lower
 // and strictly less than upper.
 //
 //	(lower..upper) = {x | lower < x < upper}
 //
 // An invalid range will be returned if lower is greater than or equal to upper.
-func Open[C Comparable](lower, upper C) Range[C] {
+func Open[C any](lower, upper C) Range[C] {
 	r, _ := OpenE(lower, upper)
 	return r
 }
@@ -18,8 +24,8 @@ func Open[C Comparable](lower, upper C) Range[C] {
 //
 // An invalid range with an error will be returned if lower is greater than or
 // equal to upper.
-func OpenE[C Comparable](lower, upper C) (Range[C], error) {
-	return create(NewAboveValue(lower), NewBelowValue(upper))
+func OpenE[C any](lower, upper C) (Range[C], error) {
+	return naturalRange(lower, OPEN, upper, OPEN)
 }
 
 // Closed returns a range that contains all values greater than or equal to
@@ -28,7 +34,7 @@ func OpenE[C Comparable](lower, upper C) (Range[C], error) {
 //	[lower..upper] = {x | lower <= x <= upper}
 //
 // An invalid range will be returned if lower is greater than upper.
-func Closed[C Comparable](lower, upper C) Range[C] {
+func Closed[C any](lower, upper C) Range[C] {
 	r, _ := ClosedE(lower, upper)
 	return r
 }
@@ -40,8 +46,8 @@ func Closed[C Comparable](lower, upper C) Range[C] {
 //
 // An invalid range with an error will be returned if lower is greater than
 // upper.
-func ClosedE[C Comparable](lower, upper C) (Range[C], error) {
-	return create(NewBelowValue(lower), NewAboveValue(upper))
+func ClosedE[C any](lower, upper C) (Range[C], error) {
+	return naturalRange(lower, CLOSED, upper, CLOSED)
 }
 
 // ClosedOpen returns a range that contains all values greater than or equal
@@ -50,7 +56,7 @@ func ClosedE[C Comparable](lower, upper C) (Range[C], error) {
 //	[lower..upper) = {x | lower <= x < upper}
 //
 // An invalid range will be returned if lower is greater than upper.
-func ClosedOpen[C Comparable](lower, upper C) Range[C] {
+func ClosedOpen[C any](lower, upper C) Range[C] {
 	r, _ := ClosedOpenE(lower, upper)
 	return r
 }
@@ -62,8 +68,8 @@ func ClosedOpen[C Comparable](lower, upper C) Range[C] {
 //
 // An invalid range with an error will be returned if lower is greater than
 // upper.
-func ClosedOpenE[C Comparable](lower, upper C) (Range[C], error) {
-	return create(NewBelowValue(lower), NewBelowValue(upper))
+func ClosedOpenE[C any](lower, upper C) (Range[C], error) {
+	return naturalRange(lower, CLOSED, upper, OPEN)
 }
 
 // OpenClosed returns a range that contains all values strictly greater than
@@ -72,7 +78,7 @@ func ClosedOpenE[C Comparable](lower, upper C) (Range[C], error) {
 //	(lower..upper] = {x | lower < x <= upper}
 //
 // An invalid range will be returned if lower is greater than upper.
-func OpenClosed[C Comparable](lower, upper C) Range[C] {
+func OpenClosed[C any](lower, upper C) Range[C] {
 	r, _ := OpenClosedE(lower, upper)
 	return r
 }
@@ -84,15 +90,15 @@ func OpenClosed[C Comparable](lower, upper C) Range[C] {
 //
 // An invalid range with an error will be returned if lower is greater than
 // upper.
-func OpenClosedE[C Comparable](lower, upper C) (Range[C], error) {
-	return create(NewAboveValue(lower), NewAboveValue(upper))
+func OpenClosedE[C any](lower, upper C) (Range[C], error) {
+	return naturalRange(lower, OPEN, upper, CLOSED)
 }
 
 // New returns a range that contains any value from lower to upper, where each
 // endpoint may be either inclusive (closed) or exclusive (open).
 //
 // An invalid range will be returned if lower is greater than upper.
-func New[C Comparable](lower C, lowerType BoundType, upper C, upperType BoundType) Range[C] {
+func New[C any](lower C, lowerType BoundType, upper C, upperType BoundType) Range[C] {
 	r, _ := NewE(lower, lowerType, upper, upperType)
 	return r
 }
@@ -102,75 +108,121 @@ func New[C Comparable](lower C, lowerType BoundType, upper C, upperType BoundTyp
 //
 // An invalid range with an error will be returned if lower is greater than
 // upper.
-func NewE[C Comparable](lower C, lowerType BoundType, upper C, upperType BoundType) (Range[C], error) {
-	var (
-		lowerBound Cut[C]
-		upperBound Cut[C]
-	)
-	if lowerType == OPEN {
-		lowerBound = NewAboveValue(lower)
-	} else {
-		lowerBound = NewBelowValue(lower)
-	}
-	if upperType == OPEN {
-		upperBound = NewBelowValue(upper)
-	} else {
-		upperBound = NewAboveValue(upper)
-	}
-	return create(lowerBound, upperBound)
+func NewE[C any](lower C, lowerType BoundType, upper C, upperType BoundType) (Range[C], error) {
+	return naturalRange(lower, lowerType, upper, upperType)
 }
 
 // LessThan returns a range that contains all values strictly less than
 // endpoint.
 //
 //	(-∞..upper) = {x | x < upper}
-func LessThan[C Comparable](upper C) Range[C] {
-	return Range[C]{lowerBound: NewBelowAll[C](), upperBound: NewBelowValue(upper)}
+func LessThan[C any](upper C) Range[C] {
+	below, ok := naturalBelowAll[C]()
+	if !ok {
+		return Invalid[C]()
+	}
+	return Range[C]{lowerBound: below, upperBound: below.belowValue(upper)}
 }
 
 // AtMost returns a range that contains all values less than or equal to
 // endpoint.
 //
 //	(-∞..upper] = {x | x <= upper}
-func AtMost[C Comparable](upper C) Range[C] {
-	return Range[C]{lowerBound: NewBelowAll[C](), upperBound: NewAboveValue(upper)}
+func AtMost[C any](upper C) Range[C] {
+	below, ok := naturalBelowAll[C]()
+	if !ok {
+		return Invalid[C]()
+	}
+	return Range[C]{lowerBound: below, upperBound: below.aboveValue(upper)}
 }
 
 // GreaterThan returns a range that contains all values strictly greater than
 // endpoint.
 //
 //	(lower..+∞) = {x | lower < x}
-func GreaterThan[C Comparable](lower C) Range[C] {
-	return Range[C]{lowerBound: NewAboveValue(lower), upperBound: NewAboveAll[C]()}
+func GreaterThan[C any](lower C) Range[C] {
+	below, ok := naturalBelowAll[C]()
+	if !ok {
+		return Invalid[C]()
+	}
+	return Range[C]{lowerBound: below.aboveValue(lower), upperBound: below.aboveAll()}
 }
 
 // AtLeast returns a range that contains all values greater than or equal to
 // endpoint.
 //
 //	[lower..+∞) = {x | lower <= x}
-func AtLeast[C Comparable](lower C) Range[C] {
-	return Range[C]{lowerBound: NewBelowValue(lower), upperBound: NewAboveAll[C]()}
+func AtLeast[C any](lower C) Range[C] {
+	below, ok := naturalBelowAll[C]()
+	if !ok {
+		return Invalid[C]()
+	}
+	return Range[C]{lowerBound: below.belowValue(lower), upperBound: below.aboveAll()}
 }
 
 // All returns a range that contains every value of type T.
 //
 //	(-∞..+∞) = {x}
-func All[C Comparable]() Range[C] {
-	return Range[C]{lowerBound: NewBelowAll[C](), upperBound: NewAboveAll[C]()}
+func All[C any]() Range[C] {
+	below, ok := naturalBelowAll[C]()
+	if !ok {
+		return Invalid[C]()
+	}
+	return Range[C]{lowerBound: below, upperBound: below.aboveAll()}
 }
 
 // Singleton returns a Range that contains only the given value.
 // The returned range is CLOSED on both ends.
 //
 //	(x) = {x}
-func Singleton[C Comparable](value C) Range[C] {
+func Singleton[C any](value C) Range[C] {
 	return Closed(value, value)
 }
 
+// EncloseAll returns the minimal range enclosing every value in values, that
+// is [min..max]. A single value yields a singleton range.
+//
+// An invalid range with an error will be returned if values is empty, as no
+// range is minimal among those enclosing no value, or if C has no natural
+// ordering.
+func EncloseAll[C any](values []C) (Range[C], error) {
+	return EncloseAllSeq(slices.Values(values))
+}
+
+// EncloseAllSeq returns the minimal range enclosing every value yielded by
+// seq, that is [min..max], consuming the whole sequence.
+//
+// An invalid range with an error will be returned if seq yields no value, or
+// if C has no natural ordering.
+func EncloseAllSeq[C any](seq iter.Seq[C]) (Range[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Invalid[C](), err
+	}
+	var lower, upper C
+	found := false
+	for value := range seq {
+		if !found {
+			lower, upper, found = value, value, true
+			continue
+		}
+		if order.compare(value, lower) < 0 {
+			lower = value
+		}
+		if order.compare(value, upper) > 0 {
+			upper = value
+		}
+	}
+	if !found {
+		return Invalid[C](), fmt.Errorf("cannot enclose no values")
+	}
+	return newRange(lower, CLOSED, upper, CLOSED, order)
+}
+
 // UpTo returns a range with no lower bound up to the given endpoint, which
 // may be either inclusive (closed) or exclusive (open).
 // An empty range with an error will be return if wrong arguments received.
-func UpTo[C Comparable](endpoint C, boundType BoundType) (Range[C], error) {
+func UpTo[C any](endpoint C, boundType BoundType) (Range[C], error) {
 	switch boundType {
 	case OPEN:
 		return LessThan(endpoint), nil
@@ -184,7 +236,7 @@ func UpTo[C Comparable](endpoint C, boundType BoundType) (Range[C], error) {
 // DownTo returns a range from the given endpoint, which may be either
 // inclusive (closed) or exclusive (open), with no upper bound.
 // An empty range with an error will be return if wrong arguments received.
-func DownTo[C Comparable](endpoint C, boundType BoundType) (Range[C], error) {
+func DownTo[C any](endpoint C, boundType BoundType) (Range[C], error) {
 	switch boundType {
 	case OPEN:
 		return GreaterThan(endpoint), nil
@@ -201,6 +253,36 @@ func DownTo[C Comparable](endpoint C, boundType BoundType) (Range[C], error) {
 // This method is useful when you need to represent the concept of
 // "no valid range" or signal an error condition in contexts where returning a
 // range is required but no meaningful range can be constructed.
-func Invalid[C Comparable]() Range[C] {
+func Invalid[C any]() Range[C] {
 	return Range[C]{invalid: true}
 }
+
+// PrefixRange returns the range containing every string that starts with
+// prefix, that is [prefix..successor) where successor is the smallest string
+// greater than all strings having the prefix.
+//
+// Strings are compared byte-wise, thus the prefix is handled as raw bytes
+// regardless of UTF-8 encoding: the successor is computed by incrementing the
+// last byte of the prefix that is not 0xFF after dropping the trailing 0xFF
+// bytes. A prefix made of 0xFF bytes only has no successor and yields
+// AtLeast(prefix), and the empty prefix yields All.
+func PrefixRange(prefix string) Range[string] {
+	if prefix == "" {
+		return All[string]()
+	}
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] != 0xFF {
+			end[i]++
+			return ClosedOpen(prefix, string(end[:i+1]))
+		}
+	}
+	return AtLeast(prefix)
+}
+
+// PrefixRangeBytes is the []byte counterpart of PrefixRange, returning the
+// range containing every key that starts with prefix. The range is expressed
+// over strings as they compare byte-wise exactly like bytes.Compare does.
+func PrefixRangeBytes(prefix []byte) Range[string] {
+	return PrefixRange(string(prefix))
+}
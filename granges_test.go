@@ -2,6 +2,7 @@ package granges_test
 
 import (
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -178,3 +179,74 @@ func assertUnboundedAbove(t *testing.T, r granges.Range[int]) {
 	_, err = r.UpperBoundTypeE()
 	assert.ErrorIs(t, err, granges.ErrUnboundedCut)
 }
+
+func TestPrefixRange(t *testing.T) {
+	tests := []struct {
+		Prefix string
+		Want   granges.Range[string]
+	}{
+		{Prefix: "", Want: granges.All[string]()},
+		{Prefix: "abc", Want: granges.ClosedOpen("abc", "abd")},
+		{Prefix: "ab\xff", Want: granges.ClosedOpen("ab\xff", "ac")},
+		{Prefix: "a\xff\xff", Want: granges.ClosedOpen("a\xff\xff", "b")},
+		{Prefix: "\xff\xff", Want: granges.AtLeast("\xff\xff")},
+		{Prefix: "\x00", Want: granges.ClosedOpen("\x00", "\x01")},
+		{Prefix: "日本", Want: granges.ClosedOpen("日本", "日\xe6\x9c\xad")},
+	}
+
+	for _, tt := range tests {
+		get := granges.PrefixRange(tt.Prefix)
+		if !get.Equal(tt.Want) {
+			t.Errorf("PrefixRange(%q) = %q, want %q", tt.Prefix, get, tt.Want)
+		}
+	}
+
+	r := granges.PrefixRange("ab\xff")
+	assert.True(t, r.Contains("ab\xff"))
+	assert.True(t, r.Contains("ab\xff\xff\xff"))
+	assert.False(t, r.Contains("ab\xfe\xff"))
+	assert.False(t, r.Contains("ac"))
+
+	r = granges.PrefixRange("\xff")
+	assert.True(t, r.Contains("\xff\xff"))
+	assert.False(t, r.Contains("\xfe"))
+}
+
+func TestPrefixRangeBytes(t *testing.T) {
+	assert.True(t, granges.ClosedOpen("key/", "key0").Equal(granges.PrefixRangeBytes([]byte("key/"))))
+	assert.True(t, granges.All[string]().Equal(granges.PrefixRangeBytes(nil)))
+}
+
+func TestEncloseAll(t *testing.T) {
+	tests := []struct {
+		Values []int
+		Want   granges.Range[int]
+	}{
+		{Values: []int{3, -1, 7, 2}, Want: granges.Closed(-1, 7)},
+		{Values: []int{5}, Want: granges.Singleton(5)},
+		{Values: []int{5, 5, 5}, Want: granges.Singleton(5)},
+		{Values: []int{9, 1}, Want: granges.Closed(1, 9)},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.EncloseAll(tt.Values)
+		assert.NoError(t, err)
+		assert.True(t, tt.Want.Equal(r), "EncloseAll(%v) = %v, want %v", tt.Values, r, tt.Want)
+
+		r, err = granges.EncloseAllSeq(slices.Values(tt.Values))
+		assert.NoError(t, err)
+		assert.True(t, tt.Want.Equal(r), "EncloseAllSeq(%v) = %v, want %v", tt.Values, r, tt.Want)
+	}
+
+	s, err := granges.EncloseAll([]string{"pear", "apple", "fig"})
+	assert.NoError(t, err)
+	assert.True(t, granges.Closed("apple", "pear").Equal(s))
+
+	r, err := granges.EncloseAll([]int{})
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+	_, err = granges.EncloseAll[int](nil)
+	assert.Error(t, err)
+	_, err = granges.EncloseAllSeq(slices.Values([]float64{}))
+	assert.Error(t, err)
+}
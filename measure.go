@@ -0,0 +1,51 @@
+package granges
+
+import "fmt"
+
+// MeasureWith returns the size of r as measured by diff, which is given the
+// upper and lower endpoints of r in that order. It allows measuring ranges of
+// any type having a meaningful notion of distance, for example the number of
+// days between two dates.
+//
+// The zero M is returned for empty ranges without calling diff. An error will
+// be returned if r is invalid, or ErrRangeSideUnbounded if r is unbounded on
+// either side.
+func MeasureWith[C, M any](r Range[C], diff func(upper, lower C) M) (M, error) {
+	var zero M
+	if r.IsInvalid() {
+		return zero, fmt.Errorf("cannot measure invalid range")
+	}
+	if !r.HasLowerBound() || !r.HasUpperBound() {
+		return zero, ErrRangeSideUnbounded
+	}
+	if r.IsEmpty() {
+		return zero, nil
+	}
+	return diff(r.UpperEndpoint(), r.LowerEndpoint()), nil
+}
+
+// MeasureSetWith returns the total size of the members of s as measured by
+// diff, see MeasureWith, which is the size of the values of s since its
+// members are disjoint. For example, with diff returning the duration between
+// two times, it returns the time covered by a set of time ranges. The zero M
+// is returned for the empty set.
+//
+// This is the RangeSet counterpart of MeasureWith; it is a function rather
+// than a method, since methods cannot take type parameters. The sizes are
+// summed with +, thus M is a numeric type, such as time.Duration; sizes of
+// other types can be summed by applying MeasureWith to the members returned by
+// AsRanges.
+//
+// ErrRangeSideUnbounded will be returned if any member of s is unbounded on
+// either side.
+func MeasureSetWith[C any, M Number](s RangeSet[C], diff func(upper, lower C) M) (M, error) {
+	var total M
+	for _, r := range s.ranges {
+		size, err := MeasureWith(r, diff)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
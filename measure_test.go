@@ -0,0 +1,69 @@
+package granges_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestMeasureWith(t *testing.T) {
+	days := func(upper, lower string) time.Duration {
+		u, _ := time.Parse(time.DateOnly, upper)
+		l, _ := time.Parse(time.DateOnly, lower)
+		return u.Sub(l)
+	}
+
+	m, err := granges.MeasureWith(granges.ClosedOpen("2024-02-01", "2024-03-01"), days)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 29*24*time.Hour, m)
+
+	called := false
+	m, err = granges.MeasureWith(granges.ClosedOpen("2024-02-01", "2024-02-01"),
+		func(upper, lower string) time.Duration {
+			called = true
+			return days(upper, lower)
+		})
+	assert.NoError(t, err)
+	assert.Zero(t, m)
+	assert.False(t, called)
+
+	_, err = granges.MeasureWith(granges.AtLeast("2024-02-01"), days)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.MeasureWith(granges.AtMost("2024-02-01"), days)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.MeasureWith(granges.Invalid[string](), days)
+	assert.Error(t, err)
+
+	width, err := granges.MeasureWith(granges.Open(1.5, 4), func(upper, lower float64) float64 {
+		return upper - lower
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2.5, width)
+}
+
+func TestMeasureSetWith(t *testing.T) {
+	s := granges.NewRangeSet(
+		byTime.ClosedOpen(day(0), day(2)),
+		byTime.ClosedOpen(day(5), day(6)),
+		byTime.ClosedOpen(day(1), day(3)),
+	)
+	total, err := granges.MeasureSetWith(s, time.Time.Sub)
+	assert.NoError(t, err)
+	assert.Equal(t, 4*24*time.Hour, total)
+
+	total, err = granges.MeasureSetWith(granges.RangeSet[time.Time]{}, time.Time.Sub)
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+
+	width, err := granges.MeasureSetWith(granges.NewRangeSet(granges.Closed(0.5, 1.5), granges.Open(3.0, 3.25)),
+		func(upper, lower float64) float64 { return upper - lower })
+	assert.NoError(t, err)
+	assert.Equal(t, 1.25, width)
+
+	s.Add(byTime.AtLeast(day(10)))
+	_, err = granges.MeasureSetWith(s, time.Time.Sub)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+}
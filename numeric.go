@@ -0,0 +1,1166 @@
+package granges
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// IsConnectedWithin returns true if r and other are connected, or if the gap
+// between them is no wider than eps. It tolerates the slivers of gap left
+// between float ranges by rounding errors, such as [0..0.9999999] and
+// [1.0000001..2]. It is a function rather than a method of Range, since
+// methods cannot narrow C to the Float types that measure a gap.
+//
+// An error will be returned if eps is negative or NaN, or if either range is
+// invalid.
+func IsConnectedWithin[C Float](r, other Range[C], eps C) (bool, error) {
+	if !(eps >= 0) {
+		return false, fmt.Errorf("tolerance must be non-negative: %v", eps)
+	}
+	if r.IsInvalid() || other.IsInvalid() {
+		return false, fmt.Errorf("cannot connect invalid ranges %s and %s", r, other)
+	}
+	if r.IsConnected(other) {
+		return true, nil
+	}
+	gap := r.Gap(other)
+	return gap.UpperEndpoint()-gap.LowerEndpoint() <= eps, nil
+}
+
+// CoalesceWithin merges ranges which are connected within eps, as defined by
+// IsConnectedWithin, returning the resulting disjoint ranges in ascending
+// order. Merging two ranges absorbs the gap between them, and the merged
+// range takes the outer bounds of its inputs. Empty ranges are dropped.
+//
+// Ranges are merged from left to right: each range is compared with the range
+// accumulated so far, so a chain of ranges separated by gaps no wider than
+// eps is merged into a single range even if the total width of the gaps
+// exceeds eps.
+//
+// An error will be returned if eps is negative or NaN, or if any range is
+// invalid.
+func CoalesceWithin[C Float](rs []Range[C], eps C) ([]Range[C], error) {
+	if !(eps >= 0) {
+		return nil, fmt.Errorf("tolerance must be non-negative: %v", eps)
+	}
+
+	sorted := make([]Range[C], 0, len(rs))
+	for i, r := range rs {
+		if r.IsInvalid() {
+			return nil, fmt.Errorf("range %d is invalid", i)
+		}
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b Range[C]) int {
+		if c := a.lowerBound.Compare(b.lowerBound); c != 0 {
+			return c
+		}
+		return a.upperBound.Compare(b.upperBound)
+	})
+
+	var coalesced []Range[C]
+	for _, r := range sorted {
+		if n := len(coalesced); n > 0 {
+			if connected, _ := IsConnectedWithin(coalesced[n-1], r, eps); connected {
+				coalesced[n-1] = coalesced[n-1].Span(r)
+				continue
+			}
+		}
+		coalesced = append(coalesced, r)
+	}
+	return coalesced, nil
+}
+
+// Shift returns r translated by delta; see ShiftE.
+//
+// An invalid range will be returned if the range cannot be shifted.
+func Shift[C Number](r Range[C], delta C) Range[C] {
+	shifted, _ := ShiftE(r, delta)
+	return shifted
+}
+
+// ShiftE returns r translated by delta: both endpoints are moved by delta,
+// while bound types and unbounded sides are preserved. For example, shifting
+// (5..+∞) by 3 yields (8..+∞), and shifting All yields All.
+//
+// An invalid range with an error will be returned if r is invalid,
+// ErrOverflow if an endpoint of an integer range does not fit in C once
+// shifted, or if the endpoints of a float range collapse into an invalid
+// range such as (x..x) once rounded.
+func ShiftE[C Number](r Range[C], delta C) (Range[C], error) {
+	if r.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot shift invalid range")
+	}
+	lower, upper := r.lowerBound, r.upperBound
+	if r.HasLowerBound() {
+		endpoint, err := checkedAdd(lower.endpoint, delta)
+		if err != nil {
+			return Invalid[C](), err
+		}
+		lower = lower.withEndpoint(endpoint)
+	}
+	if r.HasUpperBound() {
+		endpoint, err := checkedAdd(upper.endpoint, delta)
+		if err != nil {
+			return Invalid[C](), err
+		}
+		upper = upper.withEndpoint(endpoint)
+	}
+	return create(lower, upper)
+}
+
+// Chunks returns a sequence of the consecutive subranges of r of width step,
+// which together cover r; see ChunksE.
+//
+// An empty sequence will be returned if r cannot be chunked.
+func Chunks[C Number](r Range[C], step C) iter.Seq[Range[C]] {
+	chunks, err := ChunksE(r, step)
+	if err != nil {
+		return func(func(Range[C]) bool) {}
+	}
+	return chunks
+}
+
+// ChunksE returns a sequence of the consecutive subranges of r of width step,
+// which together cover r. Chunks are half-open, except that the first one
+// keeps the lower bound of r and the last one its upper bound, so that the
+// last chunk is truncated to r. For example, the chunks of (0..10] of width 3
+// are (0..3), [3..6), [6..9) and [9..10], and a range narrower than step
+// yields itself as the only chunk.
+//
+// The sequence is lazy: chunks are computed as they are pulled. For a range
+// unbounded above it is endless, unless the next chunk would start beyond
+// the values of C, in which case the last chunk is unbounded. Empty ranges
+// yield no chunk.
+//
+// A nil sequence with an error will be returned if step is not positive, if
+// r is invalid, or ErrRangeSideUnbounded if r is unbounded below.
+func ChunksE[C Number](r Range[C], step C) (iter.Seq[Range[C]], error) {
+	if !(step > 0) {
+		return nil, fmt.Errorf("step must be positive: %v", step)
+	}
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot chunk invalid range")
+	}
+	if !r.HasLowerBound() {
+		return nil, ErrRangeSideUnbounded
+	}
+	return func(yield func(Range[C]) bool) {
+		if r.IsEmpty() {
+			return
+		}
+		lower, start := r.lowerBound, r.lowerBound.endpoint
+		for {
+			next, err := checkedAdd(start, step)
+			if err != nil || next <= start {
+				yield(Range[C]{lowerBound: lower, upperBound: r.upperBound})
+				return
+			}
+			upper := NewBelowValue(next)
+			if upper.Compare(r.upperBound) >= 0 {
+				yield(Range[C]{lowerBound: lower, upperBound: r.upperBound})
+				return
+			}
+			if !yield(Range[C]{lowerBound: lower, upperBound: upper}) {
+				return
+			}
+			lower, start = upper, next
+		}
+	}, nil
+}
+
+// Chunk returns the consecutive subranges of r of width size, which together
+// cover r, as a slice; the chunks are those of ChunksE, such as [0..3),
+// [3..6), [6..9) and [9..10) for [0..10) and a size of 3. An empty range
+// yields an empty slice.
+//
+// A nil slice with an error will be returned if size is not positive, if r is
+// invalid, or ErrRangeSideUnbounded if r is unbounded on either side.
+func Chunk[C Number](r Range[C], size C) ([]Range[C], error) {
+	if !r.IsInvalid() && !r.HasUpperBound() {
+		return nil, ErrRangeSideUnbounded
+	}
+	chunks, err := ChunksE(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return slices.AppendSeq([]Range[C]{}, chunks), nil
+}
+
+// Align returns r with its endpoints snapped to multiples of step; see
+// AlignE.
+//
+// An invalid range will be returned if r cannot be aligned.
+func Align[C Integer](r Range[C], step C, outward bool) Range[C] {
+	aligned, _ := AlignE(r, step, outward)
+	return aligned
+}
+
+// AlignE returns r with its endpoints snapped to multiples of step, viewing
+// the integers as cut in buckets [k*step..(k+1)*step). Aligning outward
+// yields the span of the buckets holding any value of r, which contains every
+// value of r, while aligning inward yields the span of the buckets whose
+// values are all in r. For example, with a step of 10, [-5..25] is
+// aligned outward to [-10..30) and inward to [0..20).
+//
+// Bounded sides of the result are always closed below and open above, and
+// rounding follows floor semantics for negative endpoints. Unbounded sides
+// stay unbounded. When no bucket lies entirely in r, aligning inward yields
+// the empty range [a..a) enclosed by r, where a is the least integer of r.
+// Ranges holding no integer, such as [4..4) or (3..4), are returned
+// unchanged.
+//
+// An invalid range with an error will be returned if step is not positive,
+// if r is invalid, or ErrOverflow if an aligned endpoint does not fit in C.
+func AlignE[C Integer](r Range[C], step C, outward bool) (Range[C], error) {
+	if step <= 0 {
+		return Invalid[C](), fmt.Errorf("step must be positive: %v", step)
+	}
+	if r.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot align invalid range")
+	}
+	// first and last are the least and the greatest integers of r
+	first, last := r.lowerBound.endpoint, r.upperBound.endpoint
+	var err error
+	if r.lowerBound.cutType == AboveValue {
+		if first, err = checkedAdd(first, 1); err != nil {
+			return r, nil
+		}
+	}
+	if r.upperBound.cutType == BelowValue {
+		if last, err = checkedSub(last, 1); err != nil {
+			return r, nil
+		}
+	}
+	if r.HasLowerBound() && r.HasUpperBound() && first > last {
+		return r, nil
+	}
+
+	var aligned Bounds[C]
+	aligned.LowerInclusive = true
+	if r.HasLowerBound() {
+		lower, err := roundToMultiple(first, step, !outward)
+		if err != nil {
+			return Invalid[C](), err
+		}
+		aligned.Lower = &lower
+	}
+	if r.HasUpperBound() {
+		// the bucket of last ends at the multiple following it, which is also
+		// the end of the last bucket in r if that bucket ends with last
+		upper, err := roundToMultiple(last, step, false)
+		if err == nil && (outward || last-upper == step-1) {
+			upper, err = checkedAdd(upper, step)
+		}
+		if err != nil {
+			return Invalid[C](), err
+		}
+		aligned.Upper = &upper
+	}
+	if aligned.Lower != nil && aligned.Upper != nil && *aligned.Lower >= *aligned.Upper {
+		return ClosedOpen(first, first), nil
+	}
+	return FromBounds(aligned)
+}
+
+// roundToMultiple returns the greatest multiple of step not greater than
+// value, or the least one not less than value if up is true.
+func roundToMultiple[C Integer](value, step C, up bool) (C, error) {
+	quotient, remainder := value/step, value%step
+	if remainder < 0 && !up {
+		quotient--
+	} else if remainder > 0 && up {
+		quotient++
+	}
+	return checkedMul(quotient, step)
+}
+
+// Count returns the number of integers contained in r, such as 5 for [1..5]
+// and 3 for (1..5). Unlike Range.Count, it does not saturate: the count of
+// [math.MinInt32..math.MaxInt32] is exact, and false is returned for
+// [math.MinInt64..math.MaxInt64], whose count of 2^64 does not fit in uint64.
+//
+// False will also be returned if r is invalid or unbounded on either side.
+func Count[C Integer](r Range[C]) (uint64, bool) {
+	if r.IsInvalid() || !r.HasLowerBound() || !r.HasUpperBound() {
+		return 0, false
+	}
+	domain := IntegerDomain[C]()
+	canonical := r.Canonical(domain)
+	first := canonical.lowerBound.endpoint
+	if canonical.upperBound.cutType == BelowValue {
+		// the conversions sign-extend, so that the difference is exact
+		return uint64(canonical.upperBound.endpoint) - uint64(first), true
+	}
+	// closed at the maximum value of C
+	last, _ := domain.MaxValue()
+	count := uint64(last) - uint64(first)
+	if count == math.MaxUint64 {
+		return 0, false
+	}
+	return count + 1, true
+}
+
+// ToSlice returns the integers contained in r in ascending order, provided
+// there are no more than limit of them; see Range.ToSlice. A limit of 0 only
+// admits ranges holding no integer, such as [3..3), for which an empty,
+// non-nil slice is returned.
+//
+// An error will be returned rather than a truncated slice if r holds more than
+// limit integers. An error will also be returned if limit is negative, if r is
+// invalid, or ErrRangeSideUnbounded if r is unbounded on either side.
+func ToSlice[C Integer](r Range[C], limit int) ([]C, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative: %d", limit)
+	}
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot list values of invalid range")
+	}
+	if !r.HasLowerBound() || !r.HasUpperBound() {
+		return nil, ErrRangeSideUnbounded
+	}
+	domain := IntegerDomain[C]()
+	count, err := r.Count(domain)
+	if err != nil {
+		return nil, err
+	}
+	// a count of math.MaxInt64 may have saturated, see Range.Count
+	if count > int64(limit) || count == math.MaxInt64 {
+		return nil, fmt.Errorf("cannot list %d values of %s, limit is %d", count, r, limit)
+	}
+	return r.ToSlice(domain)
+}
+
+// Sample returns a value of r drawn uniformly at random with rng.
+//
+// For integer ranges, every integer of r is equally likely, open endpoints
+// being excluded, and any range up to [math.MinInt64..math.MaxInt64] can be
+// sampled. For float ranges, a value is drawn uniformly from the span of r
+// with a random float in [0..1), so that the upper endpoint is only drawn by
+// rounding; values landing on an open endpoint are redrawn.
+//
+// An error will be returned if r is invalid, ErrRangeSideUnbounded if r is
+// unbounded on either side, or if r holds no value, such as [4..4) or the
+// integer range (3..4).
+func Sample[C Number](r Range[C], rng *rand.Rand) (C, error) {
+	if r.IsInvalid() {
+		return 0, fmt.Errorf("cannot sample invalid range")
+	}
+	if !r.HasLowerBound() || !r.HasUpperBound() {
+		return 0, ErrRangeSideUnbounded
+	}
+	lower, upper := r.LowerEndpoint(), r.UpperEndpoint()
+	if isFloat[C]() {
+		if lower == upper && !r.IsEmpty() {
+			return lower, nil
+		}
+		if !r.IsEmpty() {
+			// a span between adjacent floats may hold no value when open
+			for range 64 {
+				u := C(rng.Float64())
+				if value := lower*(1-u) + upper*u; r.Contains(value) {
+					return value, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("cannot sample %s holding no value", r)
+	}
+
+	// first and last are the least and the greatest integers of r
+	first, last := lower, upper
+	var errFirst, errLast error
+	if r.LowerBoundType() == OPEN {
+		first, errFirst = checkedAdd(first, 1)
+	}
+	if r.UpperBoundType() == OPEN {
+		last, errLast = checkedSub(last, 1)
+	}
+	if errFirst != nil || errLast != nil || first > last {
+		return 0, fmt.Errorf("cannot sample %s holding no value", r)
+	}
+	// the difference wraps around for the widest signed ranges, yet it is
+	// right modulo 2^64, and so is the sum below
+	gap := uint64(last) - uint64(first)
+	if gap == math.MaxUint64 {
+		return first + C(rng.Uint64()), nil
+	}
+	return first + C(rng.Uint64N(gap+1)), nil
+}
+
+// Linspace returns n evenly spaced values of r in ascending order, which
+// include the endpoints of r when they are closed and exclude them when they
+// are open. For example, with n = 3, [0..1] yields 0, 0.5 and 1, [0..1)
+// yields 0, 1/3 and 2/3, and (0..1) yields 0.25, 0.5 and 0.75. A closed range
+// with n = 1 yields its lower endpoint.
+//
+// An error will be returned if n is less than 1, if r is invalid or empty, or
+// ErrRangeSideUnbounded if r is unbounded on either side.
+func Linspace[C Float](r Range[C], n int) ([]C, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("number of values must be positive: %d", n)
+	}
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot space values in invalid range")
+	}
+	if !r.HasLowerBound() || !r.HasUpperBound() {
+		return nil, ErrRangeSideUnbounded
+	}
+	if r.IsEmpty() {
+		return nil, fmt.Errorf("cannot space values in empty range %s", r)
+	}
+	lower, upper := r.LowerEndpoint(), r.UpperEndpoint()
+	// values are taken at the ends of the segments between endpoints, which
+	// are excluded when open
+	first, segments := 0, n-1
+	if r.LowerBoundType() == OPEN {
+		first, segments = 1, segments+1
+	}
+	if r.UpperBoundType() == OPEN {
+		segments++
+	}
+	if segments == 0 {
+		return []C{lower}, nil
+	}
+	values := make([]C, n)
+	for i := range values {
+		t := C(first+i) / C(segments)
+		values[i] = lower*(1-t) + upper*t
+	}
+	return values, nil
+}
+
+// Steps returns a sequence of the values start, start+step, start+2*step and
+// so on, as long as they are contained in r; see StepsE.
+//
+// An empty sequence will be returned if r cannot be stepped through.
+func Steps[C Number](r Range[C], start, step C) iter.Seq[C] {
+	steps, err := StepsE(r, start, step)
+	if err != nil {
+		return func(func(C) bool) {}
+	}
+	return steps
+}
+
+// StepsE returns a sequence of the values start, start+step, start+2*step and
+// so on, as long as they are contained in r, so that an open endpoint is never
+// yielded. For example, stepping through [0..1) from 0 by 0.25 yields 0,
+// 0.25, 0.5 and 0.75, and stepping through [0..10] from 10 by -5 yields 10, 5
+// and 0. No value is yielded if r does not contain start.
+//
+// The sequence is lazy: values are computed as they are pulled. It is endless
+// for ranges unbounded in the direction of step, except that it stops once
+// the values of C are exhausted: integer values stop before wrapping around,
+// and float values when adding step no longer changes them. Float values are
+// computed as start plus a multiple of step, so that rounding errors do not
+// pile up.
+//
+// A nil sequence with an error will be returned if step is zero or NaN, or if
+// r is invalid.
+func StepsE[C Number](r Range[C], start, step C) (iter.Seq[C], error) {
+	if !(step > 0 || step < 0) {
+		return nil, fmt.Errorf("step must be nonzero: %v", step)
+	}
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot step through invalid range")
+	}
+	return func(yield func(C) bool) {
+		value := start
+		for i := 1; r.Contains(value); i++ {
+			if !yield(value) {
+				return
+			}
+			var next C
+			var err error
+			if isFloat[C]() {
+				next = start + C(i)*step
+			} else {
+				next, err = checkedAdd(value, step)
+			}
+			if err != nil || next == value {
+				return
+			}
+			value = next
+		}
+	}, nil
+}
+
+// Length returns the width of r, the difference between its upper and lower
+// endpoints, regardless of their bound types: both [1..5] and (1..5) have a
+// length of 4. Empty ranges have a length of zero.
+//
+// An error will be returned if r is invalid, ErrRangeSideUnbounded if r is
+// unbounded on either side, or ErrOverflow if the length of an integer range
+// does not fit in C, such as the one of [math.MinInt..math.MaxInt].
+func Length[C Number](r Range[C]) (C, error) {
+	var err error
+	length, measureErr := MeasureWith(r, func(upper, lower C) C {
+		var diff C
+		diff, err = checkedSub(upper, lower)
+		return diff
+	})
+	if measureErr != nil {
+		return 0, measureErr
+	}
+	return length, err
+}
+
+// OverlapLength returns the length of the intersection of a and b, see
+// Length, or zero if they do not intersect. For example, [1..5] and [3..9)
+// overlap by 2, while the adjacent [1..3) and [3..5) overlap by zero, as do
+// [1..2] and [4..5].
+//
+// An error will be returned if either range is invalid,
+// ErrComparatorMismatch if they are ordered differently,
+// ErrRangeSideUnbounded if their intersection is unbounded on either side,
+// or ErrOverflow if its length does not fit in C.
+func OverlapLength[C Number](a, b Range[C]) (C, error) {
+	if a.IsInvalid() || b.IsInvalid() {
+		return 0, fmt.Errorf("cannot measure overlap of invalid range")
+	}
+	if err := a.checkOrder(b); err != nil {
+		return 0, err
+	}
+	if !a.IsConnected(b) {
+		return 0, nil
+	}
+	return Length(a.Intersection(b))
+}
+
+// DistanceBetween returns the length of the gap between a and b, see Gap and
+// Length, or zero if they are connected. For example, [1..3] and (7..9] are 4
+// apart, while the adjacent [1..3) and [3..5) are 0 apart, as are
+// overlapping ranges.
+//
+// An error will be returned if either range is invalid,
+// ErrComparatorMismatch if they are ordered differently, or ErrOverflow if
+// the length of the gap does not fit in C.
+func DistanceBetween[C Number](a, b Range[C]) (C, error) {
+	if a.IsInvalid() || b.IsInvalid() {
+		return 0, fmt.Errorf("cannot measure distance to invalid range")
+	}
+	if err := a.checkOrder(b); err != nil {
+		return 0, err
+	}
+	if a.IsConnected(b) {
+		return 0, nil
+	}
+	return Length(a.Gap(b))
+}
+
+// Similarity returns the Jaccard similarity of a and b, the length of their
+// intersection over the length of their span, from 0 for disjoint ranges to
+// 1 for equal ones. For example, [0..4] and [2..4] have a similarity of 0.5,
+// and adjacent ranges such as [0..1) and [1..2) a similarity of 0. Bound
+// types only matter for ranges of zero length: when the span of a and b has
+// zero length, their similarity is 1 if they are equal and 0 otherwise, so
+// that an empty range is similar to an identical empty range only.
+//
+// An error will be returned if either range is invalid,
+// ErrComparatorMismatch if they are ordered differently, or
+// ErrRangeSideUnbounded if either range is unbounded on any side.
+func Similarity[C Float](a, b Range[C]) (float64, error) {
+	overlap, err := OverlapLength(a, b)
+	if err != nil && !errors.Is(err, ErrRangeSideUnbounded) {
+		return 0, err
+	}
+	span, err := Length(a.Span(b))
+	if err != nil {
+		return 0, err
+	}
+	if span == 0 {
+		if a.Equal(b) {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return float64(overlap) / float64(span), nil
+}
+
+// RangeSetStats describes how the members of a RangeSet cover some bounds,
+// see SetStats. Members and gaps are only considered within the bounds, the
+// members straddling them being clipped.
+type RangeSetStats[C Number] struct {
+	// RangeCount is the number of members within the bounds.
+	RangeCount int
+	// CoveredMeasure is the total length of the members within the bounds.
+	CoveredMeasure C
+	// CoverageFraction is CoveredMeasure over the length of the bounds, or
+	// zero for bounds of zero length.
+	CoverageFraction float64
+	// LargestRange is the longest member within the bounds, the lowest one
+	// among equally long members, or the invalid range if there is none.
+	LargestRange Range[C]
+	// LargestGap is the longest nonempty gap between the members within the
+	// bounds, the lowest one among equally long gaps, or the invalid range if
+	// there is none.
+	LargestGap Range[C]
+	// MeanRangeMeasure is the mean length of the members within the bounds,
+	// or zero if there is none.
+	MeanRangeMeasure float64
+	// GapCount is the number of nonempty gaps between the members within the
+	// bounds, including the ones at the edges of the bounds.
+	GapCount int
+}
+
+// SetStats returns fragmentation and coverage statistics of s within bounds,
+// computed in a single pass over the members of s within bounds. For example,
+// {[0..2), [5..6)} within [0..10) holds 2 ranges covering 3, that is 30% of
+// the bounds, and leaves the 2 gaps [2..5) and [6..10), the largest being
+// [6..10).
+//
+// This is a function rather than a method of RangeSet, since the statistics
+// need arithmetic on the endpoints, which only Number types have.
+//
+// An error will be returned if bounds is invalid, ErrRangeSideUnbounded if it
+// is unbounded on either side, or ErrOverflow if a length does not fit in C.
+func SetStats[C Number](s RangeSet[C], bounds Range[C]) (RangeSetStats[C], error) {
+	stats := RangeSetStats[C]{LargestRange: Invalid[C](), LargestGap: Invalid[C]()}
+	if bounds.IsInvalid() {
+		return stats, fmt.Errorf("cannot compute statistics within invalid range")
+	}
+	boundsLength, err := Length(bounds)
+	if err != nil {
+		return stats, err
+	}
+
+	var largestRange, largestGap C
+	addGap := func(gap Range[C]) error {
+		if gap.IsEmpty() {
+			return nil
+		}
+		length, err := Length(gap)
+		if err != nil {
+			return err
+		}
+		if stats.GapCount == 0 || length > largestGap {
+			stats.LargestGap, largestGap = gap, length
+		}
+		stats.GapCount++
+		return nil
+	}
+
+	cursor := bounds.lowerBound
+	for _, r := range s.SubRangeSet(bounds).ranges {
+		if err := addGap(Range[C]{lowerBound: cursor, upperBound: r.lowerBound}); err != nil {
+			return stats, err
+		}
+		length, err := Length(r)
+		if err != nil {
+			return stats, err
+		}
+		if stats.RangeCount == 0 || length > largestRange {
+			stats.LargestRange, largestRange = r, length
+		}
+		stats.RangeCount++
+		if stats.CoveredMeasure, err = checkedAdd(stats.CoveredMeasure, length); err != nil {
+			return stats, err
+		}
+		cursor = r.upperBound
+	}
+	if err := addGap(Range[C]{lowerBound: cursor, upperBound: bounds.upperBound}); err != nil {
+		return stats, err
+	}
+
+	if boundsLength != 0 {
+		stats.CoverageFraction = float64(stats.CoveredMeasure) / float64(boundsLength)
+	}
+	if stats.RangeCount > 0 {
+		stats.MeanRangeMeasure = float64(stats.CoveredMeasure) / float64(stats.RangeCount)
+	}
+	return stats, nil
+}
+
+// Scale returns r scaled by factor about origin: the distance of each
+// endpoint from origin is multiplied by factor, while bound types and
+// unbounded sides are preserved.
+//
+// An invalid range will be returned if the range cannot be scaled, see ScaleE.
+func Scale[C Number](r Range[C], factor, origin C) Range[C] {
+	scaled, _ := ScaleE(r, factor, origin)
+	return scaled
+}
+
+// ScaleE returns r scaled by factor about origin: the distance of each
+// endpoint from origin is multiplied by factor, while bound types and
+// unbounded sides are preserved. For example, scaling [2..4) by 3 about 1
+// yields [4..10).
+//
+// A negative factor mirrors the range about origin, swapping its sides along
+// with their bound types so that the result is still a valid range: scaling
+// [2..4) by -1 about 0 yields (-4..-2], and an unbounded upper side becomes an
+// unbounded lower side. A zero factor collapses a nonempty range to the
+// singleton [origin..origin], and an empty range to [origin..origin).
+//
+// An invalid range with an error will be returned if r is invalid, if r is
+// unbounded and factor is zero, or ErrOverflow if an endpoint of an integer
+// range does not fit in C once scaled.
+func ScaleE[C Number](r Range[C], factor, origin C) (Range[C], error) {
+	if r.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot scale invalid range")
+	}
+	if factor == 0 {
+		if !r.HasLowerBound() || !r.HasUpperBound() {
+			return Invalid[C](), fmt.Errorf("cannot scale unbounded range %s by zero", r)
+		}
+		if r.IsEmpty() {
+			return ClosedOpen(origin, origin), nil
+		}
+		return Singleton(origin), nil
+	}
+
+	var lower, upper C
+	var err error
+	if r.HasLowerBound() {
+		if lower, err = scaleEndpoint(r.lowerBound.endpoint, factor, origin); err != nil {
+			return Invalid[C](), err
+		}
+	}
+	if r.HasUpperBound() {
+		if upper, err = scaleEndpoint(r.upperBound.endpoint, factor, origin); err != nil {
+			return Invalid[C](), err
+		}
+	}
+	if factor < 0 {
+		return create(r.upperBound.mirrored(upper), r.lowerBound.mirrored(lower))
+	}
+	return create(r.lowerBound.withEndpoint(lower), r.upperBound.withEndpoint(upper))
+}
+
+// AddRanges returns the sum of a and b in interval arithmetic, the range of
+// every x + y with x in a and y in b.
+//
+// An invalid range will be returned if the ranges cannot be added, see
+// AddRangesE.
+func AddRanges[C Number](a, b Range[C]) Range[C] {
+	sum, _ := AddRangesE(a, b)
+	return sum
+}
+
+// AddRangesE returns the sum of a and b in interval arithmetic, the range of
+// every x + y with x in a and y in b: its lower endpoint is the sum of the
+// lower endpoints of a and b, and its upper endpoint the sum of their upper
+// endpoints. An endpoint of the sum is closed only if both endpoints it is
+// the sum of are closed, and unbounded if either of them is unbounded. For
+// example, [1..2] + (3..4] is (4..6], and [1..2] + All is All.
+//
+// As no sum can be formed from an empty range, the sum of an empty range and
+// any valid range is that empty range.
+//
+// An invalid range with an error will be returned if either range is
+// invalid, or ErrOverflow if an endpoint of the sum of integer ranges does
+// not fit in C.
+func AddRangesE[C Number](a, b Range[C]) (Range[C], error) {
+	if a.IsInvalid() || b.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot add invalid ranges %s and %s", a, b)
+	}
+	if a.IsEmpty() {
+		return a, nil
+	}
+	if b.IsEmpty() {
+		return b, nil
+	}
+	ab, _ := ToBounds(a)
+	bb, _ := ToBounds(b)
+	var sum Bounds[C]
+	var err error
+	sum.Lower, sum.LowerInclusive, err = combineEndpoints(ab.Lower, ab.LowerInclusive, bb.Lower, bb.LowerInclusive, checkedAdd[C])
+	if err != nil {
+		return Invalid[C](), err
+	}
+	sum.Upper, sum.UpperInclusive, err = combineEndpoints(ab.Upper, ab.UpperInclusive, bb.Upper, bb.UpperInclusive, checkedAdd[C])
+	if err != nil {
+		return Invalid[C](), err
+	}
+	return FromBounds(sum)
+}
+
+// SubRanges returns the difference of a and b in interval arithmetic, the
+// range of every x - y with x in a and y in b.
+//
+// An invalid range will be returned if the ranges cannot be subtracted, see
+// SubRangesE.
+func SubRanges[C Number](a, b Range[C]) Range[C] {
+	difference, _ := SubRangesE(a, b)
+	return difference
+}
+
+// SubRangesE returns the difference of a and b in interval arithmetic, the
+// range of every x - y with x in a and y in b: its lower endpoint is the
+// lower endpoint of a minus the upper endpoint of b, and its upper endpoint
+// the upper endpoint of a minus the lower endpoint of b. Bound types and
+// unbounded sides combine as in AddRangesE. For example, [5..8] - [1..2) is
+// (3..7], and [5..8] - AtLeast(1) is AtMost(7).
+//
+// The difference of an empty range and any valid range is that empty range.
+//
+// An invalid range with an error will be returned if either range is
+// invalid, or ErrOverflow if an endpoint of the difference of integer ranges
+// does not fit in C, which includes negative endpoints for unsigned types.
+func SubRangesE[C Number](a, b Range[C]) (Range[C], error) {
+	if a.IsInvalid() || b.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot subtract invalid ranges %s and %s", a, b)
+	}
+	if a.IsEmpty() {
+		return a, nil
+	}
+	if b.IsEmpty() {
+		return b, nil
+	}
+	ab, _ := ToBounds(a)
+	bb, _ := ToBounds(b)
+	var difference Bounds[C]
+	var err error
+	difference.Lower, difference.LowerInclusive, err = combineEndpoints(ab.Lower, ab.LowerInclusive, bb.Upper, bb.UpperInclusive, checkedSub[C])
+	if err != nil {
+		return Invalid[C](), err
+	}
+	difference.Upper, difference.UpperInclusive, err = combineEndpoints(ab.Upper, ab.UpperInclusive, bb.Lower, bb.LowerInclusive, checkedSub[C])
+	if err != nil {
+		return Invalid[C](), err
+	}
+	return FromBounds(difference)
+}
+
+// MulRanges returns the product of a and b in interval arithmetic, the range
+// of every x * y with x in a and y in b.
+//
+// An invalid range will be returned if the ranges cannot be multiplied, see
+// MulRangesE.
+func MulRanges[C Number](a, b Range[C]) Range[C] {
+	product, _ := MulRangesE(a, b)
+	return product
+}
+
+// MulRangesE returns the product of a and b in interval arithmetic, the range
+// of every x * y with x in a and y in b. Its endpoints are the least and the
+// greatest of the four products of an endpoint of a and an endpoint of b, so
+// that signs are accounted for: [-2..3] * [-1..4] is [-8..12], and
+// [-3..-2] * [1..4) is (-12..-2].
+//
+// An endpoint of the product is closed if it is the product of two closed
+// endpoints, or of a closed zero endpoint and any other, as zero times any
+// value of the other range is zero. An unbounded side of either range yields
+// an unbounded side in the product unless it is multiplied by zero only: for
+// example [1..2] * AtLeast(1) is AtLeast(1), [-1..2] * AtLeast(1) is All and
+// [0..0] * All is [0..0].
+//
+// The product of an empty range and any valid range is that empty range.
+//
+// An invalid range with an error will be returned if either range is
+// invalid, or ErrOverflow if an endpoint of the product of integer ranges
+// does not fit in C.
+func MulRangesE[C Number](a, b Range[C]) (Range[C], error) {
+	if a.IsInvalid() || b.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot multiply invalid ranges %s and %s", a, b)
+	}
+	if a.IsEmpty() {
+		return a, nil
+	}
+	if b.IsEmpty() {
+		return b, nil
+	}
+	var lower, upper extendedEndpoint[C]
+	for i, x := range extendedEndpoints(a) {
+		for j, y := range extendedEndpoints(b) {
+			product, err := x.mul(y)
+			if err != nil {
+				return Invalid[C](), err
+			}
+			if i == 0 && j == 0 {
+				lower, upper = product, product
+				continue
+			}
+			lower = lower.min(product)
+			upper = upper.max(product)
+		}
+	}
+
+	var product Bounds[C]
+	if lower.infinity == 0 {
+		product.Lower, product.LowerInclusive = &lower.value, lower.closed
+	}
+	if upper.infinity == 0 {
+		product.Upper, product.UpperInclusive = &upper.value, upper.closed
+	}
+	return FromBounds(product)
+}
+
+// DivRanges returns the quotient of a and b in interval arithmetic, the range
+// of every x / y with x in a and y in b. Its endpoints are the least and the
+// greatest of the quotients of an endpoint of a by an endpoint of b, with
+// bound types combined as in MulRangesE: [2..6] / [1..2] is [1..6], and
+// [2..6] / [-2..-1) is (-6..-1].
+//
+// The divisor b may touch zero at an open endpoint, as a value near zero
+// yields a quotient unbounded on the side given by the signs of the ranges,
+// so that [1..2] / (0..4] is [0.25..+∞). Dividing by an unbounded side
+// yields quotients approaching zero.
+//
+// The quotient of an empty range and any valid range is that empty range.
+//
+// An invalid range with an error will be returned if either range is
+// invalid, or ErrDivisionByZero if b contains zero.
+func DivRanges[C Float](a, b Range[C]) (Range[C], error) {
+	if a.IsInvalid() || b.IsInvalid() {
+		return Invalid[C](), fmt.Errorf("cannot divide invalid ranges %s and %s", a, b)
+	}
+	if a.IsEmpty() {
+		return a, nil
+	}
+	if b.IsEmpty() {
+		return b, nil
+	}
+	if b.Contains(0) {
+		return Invalid[C](), fmt.Errorf("cannot divide by %s: %w", b, ErrDivisionByZero)
+	}
+	divisorSign := 1
+	if b.lowerBound.IsLessThan(0) {
+		divisorSign = -1
+	}
+
+	var lower, upper extendedEndpoint[C]
+	found := false
+	for _, x := range extendedEndpoints(a) {
+		for _, y := range extendedEndpoints(b) {
+			quotient, ok := x.div(y, divisorSign)
+			if !ok {
+				continue
+			}
+			if !found {
+				lower, upper, found = quotient, quotient, true
+				continue
+			}
+			lower = lower.min(quotient)
+			upper = upper.max(quotient)
+		}
+	}
+
+	var quotient Bounds[C]
+	if lower.infinity == 0 {
+		quotient.Lower, quotient.LowerInclusive = &lower.value, lower.closed
+	}
+	if upper.infinity == 0 {
+		quotient.Upper, quotient.UpperInclusive = &upper.value, upper.closed
+	}
+	return FromBounds(quotient)
+}
+
+// extendedEndpoint is an endpoint of a range on the extended number line:
+// either the finite value, or -∞ or +∞ when infinity is -1 or 1.
+type extendedEndpoint[C Number] struct {
+	value    C
+	infinity int
+	closed   bool
+}
+
+// extendedEndpoints returns the lower and upper endpoints of r.
+func extendedEndpoints[C Number](r Range[C]) [2]extendedEndpoint[C] {
+	b, _ := ToBounds(r)
+	endpoints := [2]extendedEndpoint[C]{{infinity: -1}, {infinity: 1}}
+	if b.Lower != nil {
+		endpoints[0] = extendedEndpoint[C]{value: *b.Lower, closed: b.LowerInclusive}
+	}
+	if b.Upper != nil {
+		endpoints[1] = extendedEndpoint[C]{value: *b.Upper, closed: b.UpperInclusive}
+	}
+	return endpoints
+}
+
+func (e extendedEndpoint[C]) sign() int {
+	switch {
+	case e.infinity != 0:
+		return e.infinity
+	case e.value > 0:
+		return 1
+	case e.value < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// isClosedZero returns true if e is a closed zero endpoint, that is if zero
+// is a value of its range.
+func (e extendedEndpoint[C]) isClosedZero() bool {
+	return e.infinity == 0 && e.value == 0 && e.closed
+}
+
+// mul returns the product of e and other, which is zero for zero times an
+// infinity. It is closed if both are closed or either is a closed zero.
+func (e extendedEndpoint[C]) mul(other extendedEndpoint[C]) (extendedEndpoint[C], error) {
+	closed := e.closed && other.closed || e.isClosedZero() || other.isClosedZero()
+	if e.infinity == 0 && other.infinity == 0 {
+		value, err := checkedMul(e.value, other.value)
+		if err != nil {
+			return e, err
+		}
+		return extendedEndpoint[C]{value: value, closed: closed}, nil
+	}
+	sign := e.sign() * other.sign()
+	if sign == 0 {
+		return extendedEndpoint[C]{closed: closed}, nil
+	}
+	return extendedEndpoint[C]{infinity: sign}, nil
+}
+
+// div returns the quotient of e by other, an endpoint of a range of the sign
+// divisorSign which does not contain zero; an open zero endpoint of that
+// range stands for values approaching zero from that side. It returns false
+// for the indeterminate forms 0/0 and ∞/∞, which bound no quotient that the
+// other endpoints do not already bound.
+func (e extendedEndpoint[C]) div(other extendedEndpoint[C], divisorSign int) (extendedEndpoint[C], bool) {
+	if e.isClosedZero() {
+		return e, true
+	}
+	switch {
+	case other.infinity != 0:
+		if e.infinity != 0 {
+			return e, false
+		}
+		return extendedEndpoint[C]{}, true
+	case other.value == 0:
+		if e.sign() == 0 {
+			return e, false
+		}
+		return extendedEndpoint[C]{infinity: e.sign() * divisorSign}, true
+	case e.infinity != 0:
+		return extendedEndpoint[C]{infinity: e.infinity * other.sign()}, true
+	default:
+		return extendedEndpoint[C]{value: e.value / other.value, closed: e.closed && other.closed}, true
+	}
+}
+
+// compare returns -1, 0 or 1 as e is less than, equal to or greater than
+// other, regardless of whether they are closed.
+func (e extendedEndpoint[C]) compare(other extendedEndpoint[C]) int {
+	switch {
+	case e.infinity != other.infinity:
+		return cmp.Compare(e.infinity, other.infinity)
+	case e.infinity != 0 || e.value == other.value:
+		return 0
+	case e.value < other.value:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// min returns the lesser of e and other, closed if either of them is closed
+// when they are equal.
+func (e extendedEndpoint[C]) min(other extendedEndpoint[C]) extendedEndpoint[C] {
+	switch e.compare(other) {
+	case -1:
+		return e
+	case 1:
+		return other
+	default:
+		e.closed = e.closed || other.closed
+		return e
+	}
+}
+
+// max returns the greater of e and other, closed if either of them is closed
+// when they are equal.
+func (e extendedEndpoint[C]) max(other extendedEndpoint[C]) extendedEndpoint[C] {
+	switch e.compare(other) {
+	case 1:
+		return e
+	case -1:
+		return other
+	default:
+		e.closed = e.closed || other.closed
+		return e
+	}
+}
+
+// combineEndpoints returns op(x, y) and whether it is inclusive, that is if
+// both x and y are, or nil if either x or y is nil as the endpoint of an
+// unbounded side.
+func combineEndpoints[C Number](x *C, xInclusive bool, y *C, yInclusive bool, op func(a, b C) (C, error)) (*C, bool, error) {
+	if x == nil || y == nil {
+		return nil, false, nil
+	}
+	endpoint, err := op(*x, *y)
+	if err != nil {
+		return nil, false, err
+	}
+	return &endpoint, xInclusive && yInclusive, nil
+}
+
+// scaleEndpoint returns origin + (endpoint - origin) * factor.
+func scaleEndpoint[C Number](endpoint, factor, origin C) (C, error) {
+	// unsigned types cannot hold a negative distance from origin
+	if endpoint >= origin {
+		distance, err := checkedSub(endpoint, origin)
+		if err != nil {
+			return 0, err
+		}
+		scaled, err := checkedMul(distance, factor)
+		if err != nil {
+			return 0, err
+		}
+		return checkedAdd(origin, scaled)
+	}
+	distance, err := checkedSub(origin, endpoint)
+	if err != nil {
+		return 0, err
+	}
+	scaled, err := checkedMul(distance, factor)
+	if err != nil {
+		return 0, err
+	}
+	return checkedSub(origin, scaled)
+}
+
+// isFloat returns true if C is a floating-point type.
+func isFloat[C Number]() bool {
+	half := C(1)
+	half /= 2
+	return half != 0
+}
+
+// checkedAdd returns a + b, or ErrOverflow if the sum of integers wraps
+// around.
+func checkedAdd[C Number](a, b C) (C, error) {
+	sum := a + b
+	if !isFloat[C]() && (b > 0 && sum < a || b < 0 && sum > a) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// checkedSub returns a - b, or ErrOverflow if the difference of integers
+// wraps around.
+func checkedSub[C Number](a, b C) (C, error) {
+	diff := a - b
+	if !isFloat[C]() && (b > 0 && diff > a || b < 0 && diff < a) {
+		return 0, ErrOverflow
+	}
+	return diff, nil
+}
+
+// checkedMul returns a * b, or ErrOverflow if the product of integers wraps
+// around.
+func checkedMul[C Number](a, b C) (C, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	product := a * b
+	if isFloat[C]() {
+		return product, nil
+	}
+	// the division alone misses the minimum signed value times -1, the only
+	// nonzero values equal to their own negation are those minimum values
+	minusOne := C(0) - 1
+	if product/b != a || b == minusOne && a == -a || a == minusOne && b == -b {
+		return 0, ErrOverflow
+	}
+	return product, nil
+}
@@ -0,0 +1,999 @@
+package granges_test
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestIsConnectedWithin(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[float64]
+		Eps  float64
+		Want bool
+	}{
+		{A: granges.Closed(0, 0.9999999), B: granges.Closed(1.0000001, 2), Eps: 1e-6, Want: true},
+		{A: granges.Closed(1.0000001, 2), B: granges.Closed(0, 0.9999999), Eps: 1e-6, Want: true},
+		{A: granges.Closed(0, 0.9999999), B: granges.Closed(1.0000001, 2), Eps: 1e-8, Want: false},
+		{A: granges.Closed(0.0, 1), B: granges.Closed(1.0, 2), Eps: 0, Want: true},
+		{A: granges.ClosedOpen(0.0, 1), B: granges.Open(1.0, 2), Eps: 0, Want: true},
+		{A: granges.Closed(0.0, 1), B: granges.Closed(3.0, 4), Eps: 2, Want: true},
+		{A: granges.Closed(0.0, 1), B: granges.Closed(3.0, 4), Eps: 1.5, Want: false},
+		{A: granges.LessThan(0.0), B: granges.GreaterThan(0.5), Eps: 0.5, Want: true},
+		{A: granges.LessThan(0.0), B: granges.GreaterThan(0.5), Eps: 0.25, Want: false},
+	}
+
+	for _, tt := range tests {
+		get, err := granges.IsConnectedWithin(tt.A, tt.B, tt.Eps)
+		assert.NoError(t, err)
+		if get != tt.Want {
+			t.Errorf("IsConnectedWithin(%v, %v, %v) = %v, want %v", tt.A, tt.B, tt.Eps, get, tt.Want)
+		}
+	}
+
+	_, err := granges.IsConnectedWithin(granges.Closed(0.0, 1), granges.Closed(2.0, 3), -1)
+	assert.Error(t, err)
+	_, err = granges.IsConnectedWithin(granges.Closed(0.0, 1), granges.Closed(2.0, 3), math.NaN())
+	assert.Error(t, err)
+	_, err = granges.IsConnectedWithin(granges.Closed(0.0, 1), granges.Invalid[float64](), 1)
+	assert.Error(t, err)
+}
+
+func TestCoalesceWithin(t *testing.T) {
+	coalesced, err := granges.CoalesceWithin([]granges.Range[float64]{
+		granges.Closed(1.0000001, 2),
+		granges.ClosedOpen(5.0, 6),
+		granges.Closed(0, 0.9999999),
+		granges.ClosedOpen(3.0, 3),
+		granges.Open(2.0000005, 2.5),
+	}, 1e-6)
+	assert.NoError(t, err)
+	assert.Len(t, coalesced, 2)
+	assert.True(t, granges.ClosedOpen(0, 2.5).Equal(coalesced[0]), "got %v", coalesced[0])
+	assert.True(t, granges.ClosedOpen(5.0, 6).Equal(coalesced[1]))
+
+	// gaps are measured against the range accumulated so far, so chains merge
+	chain := []granges.Range[float64]{
+		granges.Closed(0.0, 1), granges.Closed(1.5, 2), granges.Closed(2.5, 3), granges.Closed(3.5, 4),
+	}
+	coalesced, err = granges.CoalesceWithin(chain, 0.5)
+	assert.NoError(t, err)
+	assert.Len(t, coalesced, 1)
+	assert.True(t, granges.Closed(0.0, 4).Equal(coalesced[0]))
+
+	coalesced, err = granges.CoalesceWithin(chain, 0.4)
+	assert.NoError(t, err)
+	assert.Len(t, coalesced, 4)
+
+	coalesced, err = granges.CoalesceWithin[float64](nil, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, coalesced)
+
+	_, err = granges.CoalesceWithin(chain, -0.5)
+	assert.Error(t, err)
+	_, err = granges.CoalesceWithin([]granges.Range[float64]{granges.Invalid[float64]()}, 0)
+	assert.Error(t, err)
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		R     granges.Range[int]
+		Delta int
+		Want  granges.Range[int]
+	}{
+		{R: granges.Closed(3, 5), Delta: 10, Want: granges.Closed(13, 15)},
+		{R: granges.Open(3, 5), Delta: -10, Want: granges.Open(-7, -5)},
+		{R: granges.ClosedOpen(3, 5), Delta: 0, Want: granges.ClosedOpen(3, 5)},
+		{R: granges.OpenClosed(3, 5), Delta: 1, Want: granges.OpenClosed(4, 6)},
+		{R: granges.GreaterThan(5), Delta: 3, Want: granges.GreaterThan(8)},
+		{R: granges.AtLeast(0), Delta: 5, Want: granges.AtLeast(5)},
+		{R: granges.LessThan(5), Delta: 3, Want: granges.LessThan(8)},
+		{R: granges.AtMost(5), Delta: -3, Want: granges.AtMost(2)},
+		{R: granges.All[int](), Delta: 3, Want: granges.All[int]()},
+		{R: granges.ClosedOpen(4, 4), Delta: 2, Want: granges.ClosedOpen(6, 6)},
+	}
+
+	for _, tt := range tests {
+		get := granges.Shift(tt.R, tt.Delta)
+		if !get.Equal(tt.Want) {
+			t.Errorf("Shift(%v, %d) = %v, want %v", tt.R, tt.Delta, get, tt.Want)
+		}
+	}
+
+	assert.True(t, granges.Closed(0.75, 1.75).Equal(granges.Shift(granges.Closed(0.5, 1.5), 0.25)))
+	assert.True(t, granges.Shift(granges.Invalid[int](), 3).IsInvalid())
+
+	// shifting up to the limits of the integers
+	assert.True(t, granges.Closed(math.MaxInt-1, math.MaxInt).Equal(granges.Shift(granges.Closed(0, 1), math.MaxInt-1)))
+	assert.True(t, granges.Closed(math.MinInt, math.MinInt+1).Equal(granges.Shift(granges.Closed(-1, 0), math.MinInt+1)))
+	assert.True(t, granges.AtLeast(math.MaxInt).Equal(granges.Shift(granges.AtLeast(0), math.MaxInt)))
+}
+
+func TestShift_errors(t *testing.T) {
+	tests := []struct {
+		R     granges.Range[int]
+		Delta int
+	}{
+		{R: granges.Closed(1, math.MaxInt), Delta: 1},
+		{R: granges.Closed(math.MaxInt-1, math.MaxInt), Delta: math.MaxInt},
+		{R: granges.Closed(math.MinInt, -1), Delta: -1},
+		{R: granges.AtLeast(math.MaxInt), Delta: 1},
+		{R: granges.LessThan(math.MinInt), Delta: -1},
+		{R: granges.Closed(-1, 1), Delta: math.MaxInt},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.ShiftE(tt.R, tt.Delta)
+		assert.ErrorIs(t, err, granges.ErrOverflow, "ShiftE(%v, %d)", tt.R, tt.Delta)
+		assert.True(t, r.IsInvalid())
+		assert.True(t, granges.Shift(tt.R, tt.Delta).IsInvalid(), "Shift(%v, %d)", tt.R, tt.Delta)
+	}
+
+	_, err := granges.ShiftE(granges.Closed[uint8](250, 255), 1)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.ShiftE(granges.Invalid[int](), 1)
+	assert.Error(t, err)
+	_, err = granges.ShiftE(granges.Open(1.0, math.Nextafter(1, 2)), 1e20)
+	assert.Error(t, err)
+}
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		R     granges.Range[int]
+		Start int
+		Step  int
+		Want  []int
+	}{
+		{R: granges.Closed(0, 20), Start: 0, Step: 5, Want: []int{0, 5, 10, 15, 20}},
+		{R: granges.ClosedOpen(0, 20), Start: 0, Step: 5, Want: []int{0, 5, 10, 15}},
+		{R: granges.Closed(0, 20), Start: 3, Step: 5, Want: []int{3, 8, 13, 18}},
+		{R: granges.Closed(0, 10), Start: 10, Step: -5, Want: []int{10, 5, 0}},
+		{R: granges.OpenClosed(0, 10), Start: 10, Step: -5, Want: []int{10, 5}},
+		{R: granges.Closed(0, 10), Start: 20, Step: -5, Want: nil},
+		{R: granges.Open(0, 10), Start: 0, Step: 1, Want: nil},
+		{R: granges.AtLeast(math.MaxInt - 5), Start: math.MaxInt - 5, Step: 2, Want: []int{math.MaxInt - 5, math.MaxInt - 3, math.MaxInt - 1}},
+		{R: granges.All[int](), Start: math.MinInt + 2, Step: -1, Want: []int{math.MinInt + 2, math.MinInt + 1, math.MinInt}},
+	}
+
+	for _, tt := range tests {
+		get := slices.Collect(granges.Steps(tt.R, tt.Start, tt.Step))
+		assert.Equal(t, tt.Want, get, "Steps(%v, %d, %d)", tt.R, tt.Start, tt.Step)
+	}
+
+	assert.Equal(t, []float64{0, 0.25, 0.5, 0.75}, slices.Collect(granges.Steps(granges.ClosedOpen(0.0, 1.0), 0, 0.25)))
+	tenths := slices.Collect(granges.Steps(granges.Closed(0.0, 1.0), 0, 0.1))
+	assert.Len(t, tenths, 11)
+	assert.InDelta(t, 0.7, tenths[7], 1e-15)
+
+	// endless sequences stop when pulled no more
+	var values []int
+	for v := range granges.Steps(granges.AtLeast(0), 0, 3) {
+		if v > 9 {
+			break
+		}
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{0, 3, 6, 9}, values)
+
+	// float values which no longer change end the sequence
+	assert.Equal(t, []float64{1e20}, slices.Collect(granges.Steps(granges.AtLeast(0.0), 1e20, 1)))
+
+	for _, step := range []float64{0, math.NaN()} {
+		steps, err := granges.StepsE(granges.Closed(0.0, 1.0), 0, step)
+		assert.Error(t, err)
+		assert.Nil(t, steps)
+		assert.Empty(t, slices.Collect(granges.Steps(granges.Closed(0.0, 1.0), 0, step)))
+	}
+	_, err := granges.StepsE(granges.Invalid[int](), 0, 1)
+	assert.Error(t, err)
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want int
+	}{
+		{R: granges.Closed(1, 5), Want: 4},
+		{R: granges.Open(1, 5), Want: 4},
+		{R: granges.ClosedOpen(-3, 5), Want: 8},
+		{R: granges.Singleton(3), Want: 0},
+		{R: granges.ClosedOpen(3, 3), Want: 0},
+		{R: granges.Closed(0, math.MaxInt), Want: math.MaxInt},
+		{R: granges.Closed(math.MinInt, -1), Want: math.MaxInt},
+	}
+
+	for _, tt := range tests {
+		length, err := granges.Length(tt.R)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, length, "Length(%v)", tt.R)
+	}
+
+	length, err := granges.Length(granges.ClosedOpen(0.5, 2.0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, length)
+
+	length8, err := granges.Length(granges.Closed[uint8](0, 255))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(255), length8)
+
+	_, err = granges.Length(granges.AtLeast(1))
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Length(granges.LessThan(1.0))
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Length(granges.Closed(math.MinInt, math.MaxInt))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.Length(granges.Closed[int8](-1, 127))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.Length(granges.Invalid[int]())
+	assert.Error(t, err)
+}
+
+func TestOverlapLength(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want int
+	}{
+		{A: granges.Closed(1, 5), B: granges.ClosedOpen(3, 9), Want: 2},
+		{A: granges.Closed(1, 9), B: granges.Open(3, 5), Want: 2},
+		{A: granges.ClosedOpen(1, 3), B: granges.ClosedOpen(3, 5), Want: 0},
+		{A: granges.Closed(1, 2), B: granges.Closed(4, 5), Want: 0},
+		{A: granges.ClosedOpen(5, 5), B: granges.Closed(1, 9), Want: 0},
+		{A: granges.AtLeast(3), B: granges.Closed(1, 5), Want: 2},
+		{A: granges.All[int](), B: granges.Closed(-2, 5), Want: 7},
+		{A: granges.LessThan(0), B: granges.AtLeast(10), Want: 0},
+	}
+
+	for _, tt := range tests {
+		length, err := granges.OverlapLength(tt.A, tt.B)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, length, "OverlapLength(%v, %v)", tt.A, tt.B)
+
+		length, err = granges.OverlapLength(tt.B, tt.A)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, length, "OverlapLength(%v, %v)", tt.B, tt.A)
+	}
+
+	minutes, err := granges.OverlapLength(granges.ClosedOpen(9.0, 17.5), granges.ClosedOpen(16.0, 24.0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, minutes)
+
+	_, err = granges.OverlapLength(granges.AtLeast(1), granges.GreaterThan(3))
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.OverlapLength(granges.Closed(math.MinInt, 0), granges.Closed(-1, math.MaxInt))
+	assert.NoError(t, err)
+	_, err = granges.OverlapLength(granges.Closed(math.MinInt, math.MaxInt), granges.All[int]())
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.OverlapLength(granges.Invalid[int](), granges.Closed(1, 5))
+	assert.Error(t, err)
+	byInt := granges.NewComparator(func(a, b int) int { return a - b })
+	_, err = granges.OverlapLength(byInt.Closed(1, 5), granges.Closed(3, 7))
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+}
+
+func TestDistanceBetween(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want int
+	}{
+		{A: granges.Closed(1, 3), B: granges.OpenClosed(7, 9), Want: 4},
+		{A: granges.ClosedOpen(1, 3), B: granges.ClosedOpen(3, 5), Want: 0},
+		{A: granges.ClosedOpen(1, 3), B: granges.OpenClosed(3, 5), Want: 0},
+		{A: granges.Closed(1, 5), B: granges.Closed(3, 9), Want: 0},
+		{A: granges.LessThan(0), B: granges.AtLeast(10), Want: 10},
+		{A: granges.ClosedOpen(5, 5), B: granges.Closed(8, 9), Want: 3},
+		{A: granges.All[int](), B: granges.Singleton(3), Want: 0},
+	}
+
+	for _, tt := range tests {
+		distance, err := granges.DistanceBetween(tt.A, tt.B)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, distance, "DistanceBetween(%v, %v)", tt.A, tt.B)
+
+		distance, err = granges.DistanceBetween(tt.B, tt.A)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, distance, "DistanceBetween(%v, %v)", tt.B, tt.A)
+	}
+
+	hours, err := granges.DistanceBetween(granges.ClosedOpen(9.0, 12.5), granges.ClosedOpen(14.0, 18.0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, hours)
+
+	_, err = granges.DistanceBetween(granges.AtMost(math.MinInt), granges.AtLeast(math.MaxInt))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.DistanceBetween(granges.Closed(1, 5), granges.Invalid[int]())
+	assert.Error(t, err)
+	byInt := granges.NewComparator(func(a, b int) int { return a - b })
+	_, err = granges.DistanceBetween(byInt.Closed(1, 5), granges.Closed(7, 9))
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[float64]
+		Want float64
+	}{
+		{A: granges.Closed(0.0, 4.0), B: granges.Closed(2.0, 4.0), Want: 0.5},
+		{A: granges.Closed(0.0, 4.0), B: granges.Closed(1.0, 5.0), Want: 0.6},
+		{A: granges.Closed(0.0, 4.0), B: granges.Closed(0.0, 4.0), Want: 1},
+		{A: granges.Closed(0.0, 4.0), B: granges.ClosedOpen(0.0, 4.0), Want: 1},
+		{A: granges.Closed(0.0, 4.0), B: granges.Closed(1.0, 2.0), Want: 0.25},
+		{A: granges.ClosedOpen(0.0, 1.0), B: granges.ClosedOpen(1.0, 2.0), Want: 0},
+		{A: granges.Closed(0.0, 1.0), B: granges.Closed(3.0, 4.0), Want: 0},
+		{A: granges.ClosedOpen(2.0, 2.0), B: granges.Closed(0.0, 4.0), Want: 0},
+		// ranges of zero length are only similar to equal ranges
+		{A: granges.ClosedOpen(2.0, 2.0), B: granges.ClosedOpen(2.0, 2.0), Want: 1},
+		{A: granges.Singleton(2.0), B: granges.Singleton(2.0), Want: 1},
+		{A: granges.Singleton(2.0), B: granges.ClosedOpen(2.0, 2.0), Want: 0},
+	}
+
+	for _, tt := range tests {
+		similarity, err := granges.Similarity(tt.A, tt.B)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, similarity, "Similarity(%v, %v)", tt.A, tt.B)
+
+		similarity, err = granges.Similarity(tt.B, tt.A)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, similarity, "Similarity(%v, %v)", tt.B, tt.A)
+	}
+
+	_, err := granges.Similarity(granges.AtLeast(1.0), granges.Closed(0.0, 4.0))
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Similarity(granges.LessThan(-1.0), granges.Closed(0.0, 4.0))
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Similarity(granges.Invalid[float64](), granges.Closed(0.0, 4.0))
+	assert.Error(t, err)
+	byFloat := granges.NewComparator(cmp.Compare[float64])
+	_, err = granges.Similarity(byFloat.Closed(0, 4), granges.Closed(0.0, 4.0))
+	assert.ErrorIs(t, err, granges.ErrComparatorMismatch)
+}
+
+func TestSetStats(t *testing.T) {
+	s := granges.NewRangeSet(granges.ClosedOpen(0, 2), granges.ClosedOpen(5, 6), granges.ClosedOpen(12, 20))
+	stats, err := granges.SetStats(s, granges.ClosedOpen(0, 15))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.RangeCount)
+	assert.Equal(t, 6, stats.CoveredMeasure)
+	assert.InDelta(t, 0.4, stats.CoverageFraction, 1e-9)
+	assert.EqualValues(t, "[12..15)", stats.LargestRange.String())
+	assert.EqualValues(t, "[6..12)", stats.LargestGap.String())
+	assert.InDelta(t, 2.0, stats.MeanRangeMeasure, 1e-9)
+	assert.Equal(t, 2, stats.GapCount)
+
+	// gaps at the edges of the bounds count, and bound types flip at seams
+	floats, err := granges.SetStats(granges.NewRangeSet(granges.Closed(2.0, 3.0)), granges.Open(0.0, 10.0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, floats.RangeCount)
+	assert.Equal(t, 2, floats.GapCount)
+	assert.EqualValues(t, "(3..10)", floats.LargestGap.String())
+	assert.InDelta(t, 0.1, floats.CoverageFraction, 1e-9)
+
+	// the empty set leaves a single gap
+	stats, err = granges.SetStats(granges.RangeSet[int]{}, granges.Closed(0, 10))
+	assert.NoError(t, err)
+	assert.Zero(t, stats.RangeCount)
+	assert.Zero(t, stats.CoveredMeasure)
+	assert.Zero(t, stats.MeanRangeMeasure)
+	assert.True(t, stats.LargestRange.IsInvalid())
+	assert.EqualValues(t, "[0..10]", stats.LargestGap.String())
+	assert.Equal(t, 1, stats.GapCount)
+
+	// a set covering the bounds leaves no gap
+	stats, err = granges.SetStats(granges.NewRangeSet(granges.All[int]()), granges.ClosedOpen(0, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.RangeCount)
+	assert.Equal(t, 1.0, stats.CoverageFraction)
+	assert.True(t, stats.LargestGap.IsInvalid())
+	assert.Zero(t, stats.GapCount)
+
+	_, err = granges.SetStats(s, granges.AtLeast(0))
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.SetStats(s, granges.Invalid[int]())
+	assert.Error(t, err)
+	_, err = granges.SetStats(granges.NewRangeSet(granges.Closed[int8](-100, 100)), granges.Closed[int8](-128, 127))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		R              granges.Range[int]
+		Factor, Origin int
+		Want           granges.Range[int]
+	}{
+		{R: granges.ClosedOpen(2, 4), Factor: 3, Origin: 1, Want: granges.ClosedOpen(4, 10)},
+		{R: granges.Closed(2, 4), Factor: 2, Origin: 0, Want: granges.Closed(4, 8)},
+		{R: granges.Open(-2, 4), Factor: 2, Origin: 0, Want: granges.Open(-4, 8)},
+		{R: granges.ClosedOpen(2, 4), Factor: -1, Origin: 0, Want: granges.OpenClosed(-4, -2)},
+		{R: granges.Closed(2, 4), Factor: -2, Origin: 3, Want: granges.Closed(1, 5)},
+		{R: granges.AtLeast(2), Factor: -1, Origin: 0, Want: granges.AtMost(-2)},
+		{R: granges.LessThan(2), Factor: -2, Origin: 0, Want: granges.GreaterThan(-4)},
+		{R: granges.GreaterThan(2), Factor: 10, Origin: 0, Want: granges.GreaterThan(20)},
+		{R: granges.All[int](), Factor: -3, Origin: 7, Want: granges.All[int]()},
+		{R: granges.Closed(2, 4), Factor: 0, Origin: 3, Want: granges.Singleton(3)},
+		{R: granges.ClosedOpen(2, 2), Factor: 0, Origin: 3, Want: granges.ClosedOpen(3, 3)},
+		{R: granges.ClosedOpen(2, 2), Factor: -1, Origin: 0, Want: granges.OpenClosed(-2, -2)},
+		{R: granges.AtLeast(math.MinInt), Factor: 2, Origin: math.MinInt, Want: granges.AtLeast(math.MinInt)},
+	}
+
+	for _, tt := range tests {
+		get, err := granges.ScaleE(tt.R, tt.Factor, tt.Origin)
+		assert.NoError(t, err)
+		if !get.Equal(tt.Want) {
+			t.Errorf("ScaleE(%v, %d, %d) = %v, want %v", tt.R, tt.Factor, tt.Origin, get, tt.Want)
+		}
+		assert.True(t, tt.Want.Equal(granges.Scale(tt.R, tt.Factor, tt.Origin)))
+	}
+
+	assert.True(t, granges.Closed(0.5, 2.75).Equal(granges.Scale(granges.Closed(1.0, 2.5), 1.5, 2)))
+	assert.True(t, granges.ClosedOpen[uint](1, 9).Equal(granges.Scale(granges.ClosedOpen[uint](3, 7), 2, 5)))
+}
+
+func TestScale_errors(t *testing.T) {
+	_, err := granges.ScaleE(granges.Invalid[int](), 2, 0)
+	assert.Error(t, err)
+
+	_, err = granges.ScaleE(granges.AtLeast(1), 0, 0)
+	assert.Error(t, err)
+
+	r, err := granges.ScaleE(granges.Closed(0, math.MaxInt/2+1), 2, 0)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	assert.True(t, r.IsInvalid())
+
+	_, err = granges.ScaleE(granges.Closed(math.MinInt, 0), -1, 0)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+
+	_, err = granges.ScaleE(granges.Closed[int8](-100, 100), 1, 100)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+
+	_, err = granges.ScaleE(granges.Closed[uint8](0, 10), 2, 10)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+
+	_, err = granges.ScaleE(granges.Closed[uint8](100, 200), 2, 0)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+
+	assert.True(t, granges.Scale(granges.Closed(0, math.MaxInt), 2, 0).IsInvalid())
+}
+
+func TestAddRanges(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want granges.Range[int]
+	}{
+		{A: granges.Closed(1, 2), B: granges.Closed(3, 4), Want: granges.Closed(4, 6)},
+		{A: granges.Closed(1, 2), B: granges.OpenClosed(3, 4), Want: granges.OpenClosed(4, 6)},
+		{A: granges.ClosedOpen(1, 2), B: granges.OpenClosed(3, 4), Want: granges.Open(4, 6)},
+		{A: granges.Open(-1, 2), B: granges.Closed(-3, 4), Want: granges.Open(-4, 6)},
+		{A: granges.Singleton(1), B: granges.Singleton(2), Want: granges.Singleton(3)},
+		{A: granges.Closed(1, 2), B: granges.AtLeast(3), Want: granges.AtLeast(4)},
+		{A: granges.LessThan(1), B: granges.GreaterThan(3), Want: granges.All[int]()},
+		{A: granges.Closed(1, 2), B: granges.All[int](), Want: granges.All[int]()},
+		{A: granges.All[int](), B: granges.Open(1, 2), Want: granges.All[int]()},
+		{A: granges.ClosedOpen(5, 5), B: granges.Closed(1, 2), Want: granges.ClosedOpen(5, 5)},
+		{A: granges.Closed(1, 2), B: granges.OpenClosed(5, 5), Want: granges.OpenClosed(5, 5)},
+	}
+
+	for _, tt := range tests {
+		get, err := granges.AddRangesE(tt.A, tt.B)
+		assert.NoError(t, err)
+		if !get.Equal(tt.Want) {
+			t.Errorf("AddRangesE(%v, %v) = %v, want %v", tt.A, tt.B, get, tt.Want)
+		}
+		assert.True(t, tt.Want.Equal(granges.AddRanges(tt.B, tt.A)) || tt.A.IsEmpty() || tt.B.IsEmpty())
+	}
+
+	assert.True(t, granges.ClosedOpen(1.75, 3.0).Equal(granges.AddRanges(granges.ClosedOpen(1.5, 2.0), granges.Closed(0.25, 1.0))))
+
+	r, err := granges.AddRangesE(granges.Closed(1, math.MaxInt), granges.Closed(0, 1))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	assert.True(t, r.IsInvalid())
+	_, err = granges.AddRangesE(granges.AtMost(math.MinInt), granges.AtMost(-1))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.AddRangesE(granges.Closed[uint8](200, 250), granges.Closed[uint8](0, 10))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.AddRangesE(granges.Invalid[int](), granges.Closed(0, 1))
+	assert.Error(t, err)
+	assert.True(t, granges.AddRanges(granges.Closed(0, 1), granges.Invalid[int]()).IsInvalid())
+}
+
+func TestSubRanges(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want granges.Range[int]
+	}{
+		{A: granges.Closed(5, 8), B: granges.Closed(1, 2), Want: granges.Closed(3, 7)},
+		{A: granges.Closed(5, 8), B: granges.ClosedOpen(1, 2), Want: granges.OpenClosed(3, 7)},
+		{A: granges.ClosedOpen(5, 8), B: granges.ClosedOpen(1, 2), Want: granges.Open(3, 7)},
+		{A: granges.Closed(1, 2), B: granges.Closed(5, 8), Want: granges.Closed(-7, -3)},
+		{A: granges.Closed(1, 2), B: granges.Closed(1, 2), Want: granges.Closed(-1, 1)},
+		{A: granges.Singleton(5), B: granges.Singleton(2), Want: granges.Singleton(3)},
+		{A: granges.Closed(5, 8), B: granges.AtLeast(1), Want: granges.AtMost(7)},
+		{A: granges.Closed(5, 8), B: granges.LessThan(1), Want: granges.GreaterThan(4)},
+		{A: granges.AtLeast(5), B: granges.AtLeast(1), Want: granges.All[int]()},
+		{A: granges.All[int](), B: granges.Closed(1, 2), Want: granges.All[int]()},
+		{A: granges.ClosedOpen(5, 5), B: granges.Closed(1, 2), Want: granges.ClosedOpen(5, 5)},
+		{A: granges.Closed(1, 2), B: granges.OpenClosed(5, 5), Want: granges.OpenClosed(5, 5)},
+	}
+
+	for _, tt := range tests {
+		get, err := granges.SubRangesE(tt.A, tt.B)
+		assert.NoError(t, err)
+		if !get.Equal(tt.Want) {
+			t.Errorf("SubRangesE(%v, %v) = %v, want %v", tt.A, tt.B, get, tt.Want)
+		}
+		assert.True(t, tt.Want.Equal(granges.SubRanges(tt.A, tt.B)))
+	}
+
+	assert.True(t, granges.OpenClosed(0.5, 1.75).Equal(granges.SubRanges(granges.Closed(1.5, 2.0), granges.ClosedOpen(0.25, 1.0))))
+	assert.True(t, granges.Closed[uint](1, 7).Equal(granges.SubRanges(granges.Closed[uint](5, 8), granges.Closed[uint](1, 4))))
+
+	r, err := granges.SubRangesE(granges.Closed(math.MinInt, 0), granges.Closed(0, 1))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	assert.True(t, r.IsInvalid())
+	_, err = granges.SubRangesE(granges.AtLeast(0), granges.AtMost(math.MinInt))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.SubRangesE(granges.Closed[uint](1, 2), granges.Closed[uint](0, 3))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.SubRangesE(granges.Closed(0, 1), granges.Invalid[int]())
+	assert.Error(t, err)
+}
+
+func TestMulRanges(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want granges.Range[int]
+	}{
+		{A: granges.Closed(-2, 3), B: granges.Closed(-1, 4), Want: granges.Closed(-8, 12)},
+		{A: granges.Closed(1, 2), B: granges.Closed(3, 4), Want: granges.Closed(3, 8)},
+		{A: granges.Closed(-3, -2), B: granges.ClosedOpen(1, 4), Want: granges.OpenClosed(-12, -2)},
+		{A: granges.Closed(-3, -2), B: granges.Closed(-5, -4), Want: granges.Closed(8, 15)},
+		{A: granges.Open(-3, -2), B: granges.Closed(-5, -4), Want: granges.Open(8, 15)},
+		{A: granges.ClosedOpen(-1, 1), B: granges.ClosedOpen(-1, 1), Want: granges.OpenClosed(-1, 1)},
+		{A: granges.Closed(-2, 3), B: granges.Open(-1, 4), Want: granges.Open(-8, 12)},
+		{A: granges.Closed(0, 2), B: granges.Open(3, 4), Want: granges.ClosedOpen(0, 8)},
+		{A: granges.OpenClosed(0, 2), B: granges.Open(3, 4), Want: granges.Open(0, 8)},
+		{A: granges.Closed(1, 2), B: granges.OpenClosed(0, 3), Want: granges.OpenClosed(0, 6)},
+		{A: granges.Singleton(0), B: granges.All[int](), Want: granges.Singleton(0)},
+		{A: granges.Closed(1, 2), B: granges.AtLeast(1), Want: granges.AtLeast(1)},
+		{A: granges.Closed(-2, -1), B: granges.AtLeast(1), Want: granges.AtMost(-1)},
+		{A: granges.Closed(0, 2), B: granges.AtLeast(1), Want: granges.AtLeast(0)},
+		{A: granges.OpenClosed(0, 2), B: granges.AtLeast(1), Want: granges.GreaterThan(0)},
+		{A: granges.Closed(-1, 2), B: granges.AtLeast(1), Want: granges.All[int]()},
+		{A: granges.AtLeast(1), B: granges.AtMost(-1), Want: granges.AtMost(-1)},
+		{A: granges.LessThan(0), B: granges.LessThan(0), Want: granges.GreaterThan(0)},
+		{A: granges.Closed(1, 2), B: granges.All[int](), Want: granges.All[int]()},
+		{A: granges.ClosedOpen(5, 5), B: granges.Closed(1, 2), Want: granges.ClosedOpen(5, 5)},
+		{A: granges.Closed(1, 2), B: granges.OpenClosed(5, 5), Want: granges.OpenClosed(5, 5)},
+	}
+
+	for _, tt := range tests {
+		get, err := granges.MulRangesE(tt.A, tt.B)
+		assert.NoError(t, err)
+		if !get.Equal(tt.Want) {
+			t.Errorf("MulRangesE(%v, %v) = %v, want %v", tt.A, tt.B, get, tt.Want)
+		}
+		if !tt.A.IsEmpty() && !tt.B.IsEmpty() {
+			assert.True(t, tt.Want.Equal(granges.MulRanges(tt.B, tt.A)))
+		}
+	}
+
+	assert.True(t, granges.Closed(-1.5, 0.75).Equal(granges.MulRanges(granges.Closed(-0.5, 0.25), granges.Closed(1.0, 3.0))))
+	assert.True(t, granges.Closed[uint](3, 8).Equal(granges.MulRanges(granges.Closed[uint](1, 2), granges.Closed[uint](3, 4))))
+
+	r, err := granges.MulRangesE(granges.Closed(1, math.MaxInt), granges.Closed(1, 2))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	assert.True(t, r.IsInvalid())
+	_, err = granges.MulRangesE(granges.Closed(math.MinInt, 0), granges.Closed(-1, 0))
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.MulRangesE(granges.Closed(0, 1), granges.Invalid[int]())
+	assert.Error(t, err)
+}
+
+func TestDivRanges(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[float64]
+		Want granges.Range[float64]
+	}{
+		{A: granges.Closed(2.0, 6.0), B: granges.Closed(1.0, 2.0), Want: granges.Closed(1.0, 6.0)},
+		{A: granges.Closed(2.0, 6.0), B: granges.ClosedOpen(-2.0, -1.0), Want: granges.OpenClosed(-6.0, -1.0)},
+		{A: granges.Closed(-2.0, 6.0), B: granges.Closed(1.0, 2.0), Want: granges.Closed(-2.0, 6.0)},
+		{A: granges.Open(-2.0, 6.0), B: granges.Closed(-2.0, -1.0), Want: granges.Open(-6.0, 2.0)},
+		{A: granges.Closed(1.0, 2.0), B: granges.OpenClosed(0.0, 4.0), Want: granges.AtLeast(0.25)},
+		{A: granges.Closed(1.0, 2.0), B: granges.ClosedOpen(-4.0, 0.0), Want: granges.AtMost(-0.25)},
+		{A: granges.Closed(-1.0, 2.0), B: granges.OpenClosed(0.0, 4.0), Want: granges.All[float64]()},
+		{A: granges.Closed(0.0, 2.0), B: granges.OpenClosed(0.0, 4.0), Want: granges.AtLeast(0.0)},
+		{A: granges.OpenClosed(0.0, 2.0), B: granges.OpenClosed(0.0, 4.0), Want: granges.GreaterThan(0.0)},
+		{A: granges.Closed(1.0, 2.0), B: granges.AtLeast(1.0), Want: granges.OpenClosed(0.0, 2.0)},
+		{A: granges.AtLeast(1.0), B: granges.Closed(1.0, 2.0), Want: granges.AtLeast(0.5)},
+		{A: granges.AtLeast(1.0), B: granges.AtLeast(1.0), Want: granges.GreaterThan(0.0)},
+		{A: granges.Singleton(0.0), B: granges.GreaterThan(0.0), Want: granges.Singleton(0.0)},
+		{A: granges.ClosedOpen(5.0, 5.0), B: granges.Closed(-1.0, 1.0), Want: granges.ClosedOpen(5.0, 5.0)},
+	}
+
+	for _, tt := range tests {
+		get, err := granges.DivRanges(tt.A, tt.B)
+		assert.NoError(t, err)
+		if !get.Equal(tt.Want) {
+			t.Errorf("DivRanges(%v, %v) = %v, want %v", tt.A, tt.B, get, tt.Want)
+		}
+	}
+
+	for _, divisor := range []granges.Range[float64]{
+		granges.Closed(-1.0, 1.0), granges.Closed(0.0, 1.0), granges.OpenClosed(-1.0, 0.0), granges.Singleton(0.0), granges.All[float64](),
+	} {
+		r, err := granges.DivRanges(granges.Closed(1.0, 2.0), divisor)
+		assert.ErrorIs(t, err, granges.ErrDivisionByZero, "DivRanges([1..2], %v)", divisor)
+		assert.True(t, r.IsInvalid())
+	}
+
+	_, err := granges.DivRanges(granges.Invalid[float64](), granges.Closed(1.0, 2.0))
+	assert.Error(t, err)
+}
+
+func TestChunks(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Step int
+		Want []granges.Range[int]
+	}{
+		{R: granges.OpenClosed(0, 10), Step: 3, Want: []granges.Range[int]{
+			granges.Open(0, 3), granges.ClosedOpen(3, 6), granges.ClosedOpen(6, 9), granges.Closed(9, 10),
+		}},
+		{R: granges.ClosedOpen(0, 9), Step: 3, Want: []granges.Range[int]{
+			granges.ClosedOpen(0, 3), granges.ClosedOpen(3, 6), granges.ClosedOpen(6, 9),
+		}},
+		{R: granges.Closed(0, 9), Step: 3, Want: []granges.Range[int]{
+			granges.ClosedOpen(0, 3), granges.ClosedOpen(3, 6), granges.ClosedOpen(6, 9), granges.Singleton(9),
+		}},
+		{R: granges.Closed(-5, 5), Step: 100, Want: []granges.Range[int]{granges.Closed(-5, 5)}},
+		{R: granges.Singleton(5), Step: 1, Want: []granges.Range[int]{granges.Singleton(5)}},
+		{R: granges.ClosedOpen(5, 5), Step: 1, Want: []granges.Range[int]{}},
+		{R: granges.Closed(math.MaxInt-3, math.MaxInt), Step: 2, Want: []granges.Range[int]{
+			granges.ClosedOpen(math.MaxInt-3, math.MaxInt-1), granges.Closed(math.MaxInt-1, math.MaxInt),
+		}},
+		{R: granges.AtLeast(math.MaxInt - 3), Step: 2, Want: []granges.Range[int]{
+			granges.ClosedOpen(math.MaxInt-3, math.MaxInt-1), granges.AtLeast(math.MaxInt - 1),
+		}},
+	}
+
+	for _, tt := range tests {
+		chunks, err := granges.ChunksE(tt.R, tt.Step)
+		assert.NoError(t, err)
+		get := slices.Collect(chunks)
+		if assert.Len(t, get, len(tt.Want), "ChunksE(%v, %d) = %v", tt.R, tt.Step, get) {
+			for i := range tt.Want {
+				assert.True(t, tt.Want[i].Equal(get[i]), "ChunksE(%v, %d)[%d] = %v, want %v", tt.R, tt.Step, i, get[i], tt.Want[i])
+			}
+		}
+	}
+
+	var first []granges.Range[int]
+	for chunk := range granges.Chunks(granges.AtLeast(0), 10_000) {
+		first = append(first, chunk)
+		if len(first) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, "[[0..10000) [10000..20000) [20000..30000)]", fmt.Sprint(first))
+
+	floats := slices.Collect(granges.Chunks(granges.Closed(0.0, 1.0), 0.5))
+	assert.Equal(t, "[[0..0.5) [0.5..1)]", fmt.Sprint(floats[:2]))
+	assert.Len(t, floats, 3)
+}
+
+func TestChunks_errors(t *testing.T) {
+	for _, step := range []int{0, -1} {
+		chunks, err := granges.ChunksE(granges.Closed(0, 10), step)
+		assert.Error(t, err)
+		assert.Nil(t, chunks)
+		assert.Empty(t, slices.Collect(granges.Chunks(granges.Closed(0, 10), step)))
+	}
+
+	_, err := granges.ChunksE(granges.Closed(0.0, 1.0), math.NaN())
+	assert.Error(t, err)
+	_, err = granges.ChunksE(granges.AtMost(10), 1)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.ChunksE(granges.Invalid[int](), 1)
+	assert.Error(t, err)
+}
+
+func TestChunk(t *testing.T) {
+	chunks, err := granges.Chunk(granges.ClosedOpen(0, 10), 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[0..3) [3..6) [6..9) [9..10)]", fmt.Sprint(chunks))
+
+	chunks, err = granges.Chunk(granges.Closed(0, 9), 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[0..3) [3..6) [6..9) [9..9]]", fmt.Sprint(chunks))
+
+	floats, err := granges.Chunk(granges.Closed(0.0, 1.0), 0.5)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[0..0.5) [0.5..1) [1..1]]", fmt.Sprint(floats))
+
+	chunks, err = granges.Chunk(granges.ClosedOpen(5, 5), 3)
+	assert.NoError(t, err)
+	assert.NotNil(t, chunks)
+	assert.Empty(t, chunks)
+
+	_, err = granges.Chunk(granges.Closed(0, 10), 0)
+	assert.Error(t, err)
+	_, err = granges.Chunk(granges.AtLeast(0), 3)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Chunk(granges.AtMost(0), 3)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Chunk(granges.Invalid[int](), 3)
+	assert.Error(t, err)
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		R       granges.Range[int]
+		Step    int
+		Outward granges.Range[int]
+		Inward  granges.Range[int]
+	}{
+		{R: granges.Closed(-5, 25), Step: 10, Outward: granges.ClosedOpen(-10, 30), Inward: granges.ClosedOpen(0, 20)},
+		{R: granges.ClosedOpen(0, 20), Step: 10, Outward: granges.ClosedOpen(0, 20), Inward: granges.ClosedOpen(0, 20)},
+		{R: granges.Closed(0, 20), Step: 10, Outward: granges.ClosedOpen(0, 30), Inward: granges.ClosedOpen(0, 20)},
+		{R: granges.Closed(0, 19), Step: 10, Outward: granges.ClosedOpen(0, 20), Inward: granges.ClosedOpen(0, 20)},
+		{R: granges.Open(-1, 20), Step: 10, Outward: granges.ClosedOpen(0, 20), Inward: granges.ClosedOpen(0, 20)},
+		{R: granges.Open(0, 20), Step: 10, Outward: granges.ClosedOpen(0, 20), Inward: granges.ClosedOpen(10, 20)},
+		{R: granges.Closed(-15, -11), Step: 10, Outward: granges.ClosedOpen(-20, -10), Inward: granges.ClosedOpen(-15, -15)},
+		{R: granges.Closed(-20, -11), Step: 10, Outward: granges.ClosedOpen(-20, -10), Inward: granges.ClosedOpen(-20, -10)},
+		{R: granges.Closed(1, 3), Step: 10, Outward: granges.ClosedOpen(0, 10), Inward: granges.ClosedOpen(1, 1)},
+		{R: granges.Closed(0, 5), Step: 10, Outward: granges.ClosedOpen(0, 10), Inward: granges.ClosedOpen(0, 0)},
+		{R: granges.Singleton(7), Step: 1, Outward: granges.ClosedOpen(7, 8), Inward: granges.ClosedOpen(7, 8)},
+		{R: granges.AtLeast(-5), Step: 10, Outward: granges.AtLeast(-10), Inward: granges.AtLeast(0)},
+		{R: granges.LessThan(-5), Step: 10, Outward: granges.LessThan(0), Inward: granges.LessThan(-10)},
+		{R: granges.All[int](), Step: 10, Outward: granges.All[int](), Inward: granges.All[int]()},
+		{R: granges.ClosedOpen(4, 4), Step: 10, Outward: granges.ClosedOpen(4, 4), Inward: granges.ClosedOpen(4, 4)},
+		{R: granges.Open(3, 4), Step: 10, Outward: granges.Open(3, 4), Inward: granges.Open(3, 4)},
+		{R: granges.GreaterThan(math.MaxInt), Step: 10, Outward: granges.GreaterThan(math.MaxInt), Inward: granges.GreaterThan(math.MaxInt)},
+	}
+
+	for _, tt := range tests {
+		outward, err := granges.AlignE(tt.R, tt.Step, true)
+		assert.NoError(t, err)
+		assert.True(t, tt.Outward.Equal(outward), "AlignE(%v, %d, true) = %v, want %v", tt.R, tt.Step, outward, tt.Outward)
+		assert.True(t, outward.ContainsRange(tt.R))
+
+		inward, err := granges.AlignE(tt.R, tt.Step, false)
+		assert.NoError(t, err)
+		assert.True(t, tt.Inward.Equal(inward), "AlignE(%v, %d, false) = %v, want %v", tt.R, tt.Step, inward, tt.Inward)
+		assert.True(t, tt.R.ContainsRange(inward))
+	}
+
+	assert.True(t, granges.ClosedOpen[uint](4096, 12288).Equal(granges.Align(granges.Closed[uint](5000, 9000), 4096, true)))
+	assert.True(t, granges.ClosedOpen[uint](8192, 8192).Equal(granges.Align(granges.Closed[uint](8192, 9000), 4096, false)))
+}
+
+func TestAlign_errors(t *testing.T) {
+	r, err := granges.AlignE(granges.Closed(0, 10), 0, true)
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+	_, err = granges.AlignE(granges.Closed(0, 10), -5, true)
+	assert.Error(t, err)
+	_, err = granges.AlignE(granges.Invalid[int](), 5, true)
+	assert.Error(t, err)
+	_, err = granges.AlignE(granges.Closed(0, math.MaxInt-1), 10, true)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	_, err = granges.AlignE(granges.Closed[int8](-127, 0), 10, true)
+	assert.ErrorIs(t, err, granges.ErrOverflow)
+	assert.True(t, granges.Align(granges.Closed[int8](-127, 0), 10, true).IsInvalid())
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want uint64
+	}{
+		{R: granges.Closed(1, 5), Want: 5},
+		{R: granges.ClosedOpen(1, 5), Want: 4},
+		{R: granges.Open(1, 5), Want: 3},
+		{R: granges.Open(1, 2), Want: 0},
+		{R: granges.ClosedOpen(3, 3), Want: 0},
+		{R: granges.Singleton(-7), Want: 1},
+		{R: granges.Closed(-2, 2), Want: 5},
+		{R: granges.OpenClosed(math.MaxInt-1, math.MaxInt), Want: 1},
+		{R: granges.ClosedOpen(math.MinInt, math.MinInt+1), Want: 1},
+		{R: granges.ClosedOpen(math.MinInt, math.MaxInt), Want: math.MaxUint64},
+		{R: granges.OpenClosed(math.MinInt, math.MaxInt), Want: math.MaxUint64},
+	}
+
+	for _, tt := range tests {
+		count, ok := granges.Count(tt.R)
+		assert.True(t, ok, "Count(%v)", tt.R)
+		assert.Equal(t, tt.Want, count, "Count(%v)", tt.R)
+	}
+
+	count, ok := granges.Count(granges.Closed[int32](math.MinInt32, math.MaxInt32))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1<<32), count)
+	count, ok = granges.Count(granges.Closed[uint8](0, 255))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(256), count)
+	count, ok = granges.Count(granges.Closed[uint64](0, math.MaxUint64-1))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(math.MaxUint64), count)
+
+	for _, r := range []granges.Range[int]{
+		granges.Closed(math.MinInt, math.MaxInt),
+		granges.AtLeast(0),
+		granges.LessThan(0),
+		granges.All[int](),
+		granges.Invalid[int](),
+	} {
+		_, ok := granges.Count(r)
+		assert.False(t, ok, "Count(%v)", r)
+	}
+	_, ok = granges.Count(granges.Closed[uint64](0, math.MaxUint64))
+	assert.False(t, ok)
+}
+
+func TestToSlice(t *testing.T) {
+	tests := []struct {
+		R     granges.Range[int]
+		Limit int
+		Want  []int
+	}{
+		{R: granges.Closed(1, 5), Limit: 5, Want: []int{1, 2, 3, 4, 5}},
+		{R: granges.Open(1, 5), Limit: 10, Want: []int{2, 3, 4}},
+		{R: granges.ClosedOpen(-2, 1), Limit: 3, Want: []int{-2, -1, 0}},
+		{R: granges.Singleton(7), Limit: 1, Want: []int{7}},
+		{R: granges.ClosedOpen(3, 3), Limit: 0, Want: []int{}},
+		{R: granges.Open(3, 4), Limit: 0, Want: []int{}},
+		{R: granges.Closed(math.MaxInt-1, math.MaxInt), Limit: 2, Want: []int{math.MaxInt - 1, math.MaxInt}},
+	}
+
+	for _, tt := range tests {
+		values, err := granges.ToSlice(tt.R, tt.Limit)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, values, "ToSlice(%v, %d)", tt.R, tt.Limit)
+	}
+
+	values, err := granges.ToSlice(granges.Closed[uint8](0, 255), 256)
+	assert.NoError(t, err)
+	assert.Len(t, values, 256)
+}
+
+func TestToSlice_errors(t *testing.T) {
+	values, err := granges.ToSlice(granges.Closed(1, 5), 4)
+	assert.Error(t, err)
+	assert.Nil(t, values)
+	_, err = granges.ToSlice(granges.Singleton(1), 0)
+	assert.Error(t, err)
+	_, err = granges.ToSlice(granges.Closed(1, 5), -1)
+	assert.Error(t, err)
+	_, err = granges.ToSlice(granges.Closed(math.MinInt, math.MaxInt), math.MaxInt)
+	assert.Error(t, err)
+	_, err = granges.ToSlice(granges.Invalid[int](), 10)
+	assert.Error(t, err)
+	_, err = granges.ToSlice(granges.AtLeast(math.MaxInt-1), 10)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.ToSlice(granges.LessThan(0), 10)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+}
+
+func TestSample(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	seen := map[int]int{}
+	for range 1000 {
+		v, err := granges.Sample(granges.OpenClosed(0, 4), rng)
+		assert.NoError(t, err)
+		seen[v]++
+	}
+	assert.Len(t, seen, 4)
+	for v := 1; v <= 4; v++ {
+		assert.Greater(t, seen[v], 150, "value %d drawn %d times", v, seen[v])
+	}
+
+	for _, r := range []granges.Range[int]{
+		granges.Singleton(7), granges.Open(6, 8), granges.Closed(-3, -1), granges.Closed(math.MinInt, math.MaxInt), granges.Open(math.MinInt, math.MaxInt),
+		granges.Closed(math.MaxInt-1, math.MaxInt), granges.Closed(math.MinInt, math.MinInt+1),
+	} {
+		for range 100 {
+			v, err := granges.Sample(r, rng)
+			assert.NoError(t, err)
+			assert.True(t, r.Contains(v), "Sample(%v) = %d", r, v)
+		}
+	}
+
+	for _, r := range []granges.Range[uint8]{granges.Closed[uint8](0, 255), granges.Open[uint8](0, 2)} {
+		for range 100 {
+			v, err := granges.Sample(r, rng)
+			assert.NoError(t, err)
+			assert.True(t, r.Contains(v), "Sample(%v) = %d", r, v)
+		}
+	}
+
+	for _, r := range []granges.Range[float64]{
+		granges.Closed(0.0, 1.0), granges.Open(-1.0, 1.0), granges.Singleton(0.1), granges.Closed(-math.MaxFloat64, math.MaxFloat64),
+		granges.Open(1.0, math.Nextafter(math.Nextafter(1, 2), 2)),
+	} {
+		for range 100 {
+			v, err := granges.Sample(r, rng)
+			assert.NoError(t, err)
+			assert.True(t, r.Contains(v), "Sample(%v) = %v", r, v)
+		}
+	}
+
+	a, _ := granges.Sample(granges.Closed(0, 1_000_000), rand.New(rand.NewPCG(3, 4)))
+	b, _ := granges.Sample(granges.Closed(0, 1_000_000), rand.New(rand.NewPCG(3, 4)))
+	assert.Equal(t, a, b)
+}
+
+func TestSample_errors(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for _, r := range []granges.Range[int]{
+		granges.Invalid[int](), granges.AtLeast(0), granges.AtMost(0), granges.ClosedOpen(4, 4), granges.Open(3, 4),
+		granges.OpenClosed(math.MaxInt, math.MaxInt), granges.ClosedOpen(math.MinInt, math.MinInt),
+	} {
+		_, err := granges.Sample(r, rng)
+		assert.Error(t, err, "Sample(%v)", r)
+	}
+
+	_, err := granges.Sample(granges.AtLeast(0.5), rng)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Sample(granges.Open(1.0, math.Nextafter(1, 2)), rng)
+	assert.Error(t, err)
+	_, err = granges.Sample(granges.ClosedOpen(0.5, 0.5), rng)
+	assert.Error(t, err)
+}
+
+func TestLinspace(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[float64]
+		N    int
+		Want []float64
+	}{
+		{R: granges.Closed(0.0, 1.0), N: 3, Want: []float64{0, 0.5, 1}},
+		{R: granges.Closed(0.0, 1.0), N: 5, Want: []float64{0, 0.25, 0.5, 0.75, 1}},
+		{R: granges.ClosedOpen(0.0, 1.0), N: 4, Want: []float64{0, 0.25, 0.5, 0.75}},
+		{R: granges.OpenClosed(0.0, 1.0), N: 4, Want: []float64{0.25, 0.5, 0.75, 1}},
+		{R: granges.Open(0.0, 1.0), N: 3, Want: []float64{0.25, 0.5, 0.75}},
+		{R: granges.Open(-2.0, 2.0), N: 1, Want: []float64{0}},
+		{R: granges.Closed(-2.0, 2.0), N: 1, Want: []float64{-2}},
+		{R: granges.Closed(-2.0, 2.0), N: 2, Want: []float64{-2, 2}},
+		{R: granges.Singleton(3.0), N: 2, Want: []float64{3, 3}},
+		{R: granges.Closed(-math.MaxFloat64, math.MaxFloat64), N: 3, Want: []float64{-math.MaxFloat64, 0, math.MaxFloat64}},
+	}
+
+	for _, tt := range tests {
+		values, err := granges.Linspace(tt.R, tt.N)
+		assert.NoError(t, err)
+		assert.InDeltaSlice(t, tt.Want, values, 1e-12, "Linspace(%v, %d)", tt.R, tt.N)
+		for _, v := range values {
+			assert.True(t, tt.R.Contains(v), "Linspace(%v, %d) yields %v", tt.R, tt.N, v)
+		}
+	}
+
+	values, err := granges.Linspace(granges.Closed(0.1, 0.7), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, values[0])
+	assert.Equal(t, 0.7, values[6])
+
+	values32, err := granges.Linspace(granges.ClosedOpen[float32](0, 1), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 0.5}, values32)
+}
+
+func TestLinspace_errors(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		_, err := granges.Linspace(granges.Closed(0.0, 1.0), n)
+		assert.Error(t, err)
+	}
+
+	_, err := granges.Linspace(granges.AtLeast(0.0), 3)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Linspace(granges.ClosedOpen(1.0, 1.0), 3)
+	assert.Error(t, err)
+	_, err = granges.Linspace(granges.Invalid[float64](), 3)
+	assert.Error(t, err)
+}
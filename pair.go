@@ -0,0 +1,59 @@
+package granges
+
+import "fmt"
+
+// Pair is a composite key of two values ordered lexicographically: by First,
+// then by Second. Pair is a Comparer, so that ranges of pairs are built with
+// the usual constructors, such as a scan window over the keys of tenant 7
+// from t1 (inclusive) to t2 (exclusive):
+//
+//	granges.ClosedOpen(granges.PairOf(7, t1), granges.PairOf(7, t2))
+type Pair[A, B Comparable] struct {
+	First  A
+	Second B
+
+	// side places the pair below (-1) or above (1) every pair having the
+	// same First, whatever Second is, for the bounds of PrefixRangeFor.
+	side int8
+}
+
+// PairOf returns the pair (first, second).
+func PairOf[A, B Comparable](first A, second B) Pair[A, B] {
+	return Pair[A, B]{First: first, Second: second}
+}
+
+// Compare compares this pair with other lexicographically, returning a
+// negative number, zero or a positive number as this pair is less than, equal
+// to or greater than other.
+func (p Pair[A, B]) Compare(other Pair[A, B]) int {
+	if n := compareOperators(p.First, other.First); n != 0 {
+		return n
+	}
+	if p.side != other.side {
+		return int(p.side) - int(other.side)
+	}
+	if p.side != 0 {
+		return 0
+	}
+	return compareOperators(p.Second, other.Second)
+}
+
+// String renders this pair as a tuple, such as (7, 1700000000). The bounds of
+// PrefixRangeFor render their second component as -∞ or +∞.
+func (p Pair[A, B]) String() string {
+	switch p.side {
+	case -1:
+		return fmt.Sprintf("(%v, -∞)", p.First)
+	case 1:
+		return fmt.Sprintf("(%v, +∞)", p.First)
+	default:
+		return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+	}
+}
+
+// PrefixRangeFor returns the range containing every pair whose first
+// component is first, whatever its second component is, rendered as
+// ((first, -∞)..(first, +∞)).
+func PrefixRangeFor[A, B Comparable](first A) Range[Pair[A, B]] {
+	return Open(Pair[A, B]{First: first, side: -1}, Pair[A, B]{First: first, side: 1})
+}
@@ -0,0 +1,55 @@
+package granges_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestPair_Compare(t *testing.T) {
+	assert.Negative(t, granges.PairOf(1, "z").Compare(granges.PairOf(2, "a")))
+	assert.Negative(t, granges.PairOf(1, "a").Compare(granges.PairOf(1, "b")))
+	assert.Positive(t, granges.PairOf(2, "a").Compare(granges.PairOf(1, "z")))
+	assert.Zero(t, granges.PairOf(1, "a").Compare(granges.PairOf(1, "a")))
+}
+
+func TestPair_ranges(t *testing.T) {
+	window := granges.ClosedOpen(granges.PairOf(7, int64(100)), granges.PairOf(7, int64(200)))
+	assert.False(t, window.IsInvalid())
+	assert.EqualValues(t, "[(7, 100)..(7, 200))", window.String())
+	assert.True(t, window.Contains(granges.PairOf(7, int64(100))))
+	assert.True(t, window.Contains(granges.PairOf(7, int64(150))))
+	assert.False(t, window.Contains(granges.PairOf(7, int64(200))))
+	assert.False(t, window.Contains(granges.PairOf(6, int64(150))))
+	assert.False(t, window.Contains(granges.PairOf(8, int64(150))))
+
+	other := granges.Closed(granges.PairOf(7, int64(150)), granges.PairOf(9, int64(0)))
+	assert.EqualValues(t, "[(7, 150)..(7, 200))", window.Intersection(other).String())
+
+	// a window across tenants holds every key of the tenants in between
+	across := granges.Closed(granges.PairOf(6, int64(500)), granges.PairOf(8, int64(0)))
+	assert.True(t, across.Contains(granges.PairOf(7, int64(-1))))
+	assert.True(t, granges.ClosedOpen(granges.PairOf(1, 0), granges.PairOf(1, 0)).IsEmpty())
+	assert.True(t, granges.Closed(granges.PairOf(2, 0), granges.PairOf(1, 5)).IsInvalid())
+}
+
+func TestPrefixRangeFor(t *testing.T) {
+	tenant := granges.PrefixRangeFor[int, int64](7)
+	assert.EqualValues(t, "((7, -∞)..(7, +∞))", tenant.String())
+	assert.True(t, tenant.Contains(granges.PairOf[int, int64](7, -1<<63)))
+	assert.True(t, tenant.Contains(granges.PairOf[int, int64](7, 1<<63-1)))
+	assert.False(t, tenant.Contains(granges.PairOf[int, int64](6, 1<<63-1)))
+	assert.False(t, tenant.Contains(granges.PairOf[int, int64](8, -1<<63)))
+
+	window := granges.ClosedOpen(granges.PairOf[int, int64](7, 100), granges.PairOf[int, int64](9, 0))
+	assert.True(t, tenant.Intersects(window))
+	assert.EqualValues(t, "[(7, 100)..(7, +∞))", tenant.Intersection(window).String())
+	assert.True(t, tenant.Encloses(granges.Closed(granges.PairOf[int, int64](7, 0), granges.PairOf[int, int64](7, 5))))
+
+	names := granges.PrefixRangeFor[string, string]("b")
+	assert.True(t, names.Contains(granges.PairOf("b", "")))
+	assert.False(t, names.Contains(granges.PairOf("a", "zzz")))
+	assert.False(t, names.Contains(granges.PairOf("ba", "")))
+}
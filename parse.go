@@ -0,0 +1,165 @@
+package granges
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Parse parses s in the notation of Range.String back into a range, such as
+// [4..8), (1..5] or (-∞..5), converting each endpoint with parseValue. For
+// example,
+//
+//	r, err := granges.Parse("(1..5]", strconv.Atoi)
+//
+// Unbounded sides are written as -∞ and +∞ and must be open. Unless C is a
+// float type, -inf and +inf, ignoring case, are accepted as well; for float
+// types they are left to parseValue, being the spellings of infinite values,
+// so that Parse("[-Inf..0]", ...) returns a range closed at -Inf. Whitespace
+// around s and around each endpoint is ignored. The endpoints are separated
+// at the first "..", so a lower endpoint cannot contain "..".
+//
+// An invalid range with an error naming the offending part of s will be
+// returned if s is malformed, if parseValue fails for an endpoint, or if the
+// range would be invalid, such as (4..4) or [5..4].
+func Parse[C any](s string, parseValue func(string) (C, error)) (Range[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Invalid[C](), err
+	}
+	return parse(s, parseValue, order)
+}
+
+// parse parses s like Parse does into a range whose cuts carry order.
+func parse[C any](s string, parseValue func(string) (C, error), order *ordering[C]) (Range[C], error) {
+	lowerBracket, lowerText, upperText, upperBracket, err := cutBrackets(s, "..")
+	if err != nil {
+		return Invalid[C](), err
+	}
+	lowerText, upperText = strings.TrimSpace(lowerText), strings.TrimSpace(upperText)
+
+	lowerBound := Cut[C]{cutType: BelowAll, order: order}
+	upperBound := lowerBound.aboveAll()
+	if isInfinity[C](lowerText, '-') {
+		if lowerBracket != '(' {
+			return Invalid[C](), fmt.Errorf("invalid range %q: unbounded lower side %q must be open", s, lowerText)
+		}
+	} else {
+		lower, err := parseValue(lowerText)
+		if err != nil {
+			return Invalid[C](), fmt.Errorf("invalid range %q: lower endpoint %q: %w", s, lowerText, err)
+		}
+		if lowerBracket == '[' {
+			lowerBound = lowerBound.belowValue(lower)
+		} else {
+			lowerBound = lowerBound.aboveValue(lower)
+		}
+	}
+	if isInfinity[C](upperText, '+') {
+		if upperBracket != ')' {
+			return Invalid[C](), fmt.Errorf("invalid range %q: unbounded upper side %q must be open", s, upperText)
+		}
+	} else {
+		upper, err := parseValue(upperText)
+		if err != nil {
+			return Invalid[C](), fmt.Errorf("invalid range %q: upper endpoint %q: %w", s, upperText, err)
+		}
+		if upperBracket == ']' {
+			upperBound = upperBound.aboveValue(upper)
+		} else {
+			upperBound = upperBound.belowValue(upper)
+		}
+	}
+
+	return create(lowerBound, upperBound)
+}
+
+// ParseInterval parses s in the mathematical notation using a comma as the
+// separator, such as [1,5), (0, 1] or (,10], converting each endpoint with
+// parseValue. This is also the text format of PostgreSQL range types.
+//
+// An empty side is unbounded, as are -∞ and +∞, and -inf and +inf for types
+// other than floats, as for Parse. Following PostgreSQL, the bracket of an
+// unbounded side is ignored, so that [,10] is the same as (,10]. Whitespace
+// around s and around each endpoint is ignored. The endpoints are separated
+// at the first comma, so a lower endpoint cannot contain a comma.
+//
+// An invalid range with an error naming the offending part of s will be
+// returned if s is malformed, such as with a missing bracket, if parseValue
+// fails for a non-empty endpoint, or if the range would be invalid, such as
+// (4,4) or [5,4].
+func ParseInterval[C any](s string, parseValue func(string) (C, error)) (Range[C], error) {
+	order, err := orderOf[C]()
+	if err != nil {
+		return Invalid[C](), err
+	}
+	return parseInterval(s, parseValue, order)
+}
+
+// parseInterval parses s like ParseInterval does into a range whose cuts carry
+// order.
+func parseInterval[C any](s string, parseValue func(string) (C, error), order *ordering[C]) (Range[C], error) {
+	lowerBracket, lowerText, upperText, upperBracket, err := cutBrackets(s, ",")
+	if err != nil {
+		return Invalid[C](), err
+	}
+	lowerText, upperText = strings.TrimSpace(lowerText), strings.TrimSpace(upperText)
+
+	var b Bounds[C]
+	if lowerText != "" && !isInfinity[C](lowerText, '-') {
+		lower, err := parseValue(lowerText)
+		if err != nil {
+			return Invalid[C](), fmt.Errorf("invalid range %q: lower endpoint %q: %w", s, lowerText, err)
+		}
+		b.Lower, b.LowerInclusive = &lower, lowerBracket == '['
+	}
+	if upperText != "" && !isInfinity[C](upperText, '+') {
+		upper, err := parseValue(upperText)
+		if err != nil {
+			return Invalid[C](), fmt.Errorf("invalid range %q: upper endpoint %q: %w", s, upperText, err)
+		}
+		b.Upper, b.UpperInclusive = &upper, upperBracket == ']'
+	}
+	return fromBounds(b, order)
+}
+
+// cutBrackets splits s, without surrounding whitespace, into its brackets and
+// the texts of its endpoints around the first separator.
+func cutBrackets(s, separator string) (lowerBracket byte, lowerText, upperText string, upperBracket byte, err error) {
+	text := strings.TrimSpace(s)
+	if len(text) < 2 {
+		return 0, "", "", 0, fmt.Errorf("invalid range %q: too short", s)
+	}
+	lowerBracket, upperBracket = text[0], text[len(text)-1]
+	if lowerBracket != '[' && lowerBracket != '(' {
+		return 0, "", "", 0, fmt.Errorf("invalid range %q: unexpected lower bracket %q", s, lowerBracket)
+	}
+	if upperBracket != ']' && upperBracket != ')' {
+		return 0, "", "", 0, fmt.Errorf("invalid range %q: unexpected upper bracket %q", s, upperBracket)
+	}
+	lowerText, upperText, found := strings.Cut(text[1:len(text)-1], separator)
+	if !found {
+		return 0, "", "", 0, fmt.Errorf("invalid range %q: missing %q separator", s, separator)
+	}
+	return lowerBracket, lowerText, upperText, upperBracket, nil
+}
+
+// isInfinity returns true if text is ∞ preceded by sign, marking an unbounded
+// side. The ASCII spelling inf, ignoring case, is accepted as well unless C is
+// a float type: floats have infinite values, formatted as -Inf and +Inf, which
+// are then endpoints, so that [-Inf..0] is a closed range and ranges with
+// infinite endpoints round-trip through String.
+func isInfinity[C any](text string, sign byte) bool {
+	if len(text) == 0 || text[0] != sign {
+		return false
+	}
+	if text[1:] == "∞" {
+		return true
+	}
+	switch reflect.TypeFor[C]().Kind() {
+	case reflect.Float32, reflect.Float64:
+		return false
+	default:
+		return strings.EqualFold(text[1:], "inf")
+	}
+}
@@ -0,0 +1,177 @@
+package granges_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestParse(t *testing.T) {
+	tests := []granges.Range[int]{
+		granges.Open(1, 5),
+		granges.Closed(1, 5),
+		granges.ClosedOpen(-1, 5),
+		granges.OpenClosed(1, 5),
+		granges.LessThan(5),
+		granges.AtMost(5),
+		granges.GreaterThan(-5),
+		granges.AtLeast(5),
+		granges.All[int](),
+		granges.Singleton(3),
+		granges.ClosedOpen(3, 3),
+	}
+
+	for _, want := range tests {
+		r, err := granges.Parse(want.String(), strconv.Atoi)
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(r), "Parse(%q) = %v, want %v", want.String(), r, want)
+	}
+
+	r, err := granges.Parse(" \t[ 1 .. 5 )\n", strconv.Atoi)
+	assert.NoError(t, err)
+	assert.True(t, granges.ClosedOpen(1, 5).Equal(r))
+
+	r, err = granges.Parse("(-INF..+inf)", strconv.Atoi)
+	assert.NoError(t, err)
+	assert.True(t, granges.All[int]().Equal(r))
+
+	s, err := granges.Parse("[apple..cherry)", func(s string) (string, error) { return s, nil })
+	assert.NoError(t, err)
+	assert.True(t, granges.ClosedOpen("apple", "cherry").Equal(s))
+
+	d, err := granges.Parse("(1s..1m30s]", time.ParseDuration)
+	assert.NoError(t, err)
+	assert.True(t, granges.OpenClosed(time.Second, 90*time.Second).Equal(d))
+}
+
+func TestParse_infinities(t *testing.T) {
+	parseFloat := func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
+	inf := math.Inf(1)
+
+	r, err := granges.Parse("[-Inf..0]", parseFloat)
+	assert.NoError(t, err)
+	assert.True(t, granges.Closed(-inf, 0).Equal(r))
+
+	r, err = granges.Parse("(-inf..0)", parseFloat)
+	assert.NoError(t, err)
+	assert.True(t, granges.Open(-inf, 0).Equal(r))
+	assert.False(t, granges.LessThan(0.0).Equal(r))
+
+	r, err = granges.Parse("(-∞..+∞)", parseFloat)
+	assert.NoError(t, err)
+	assert.True(t, granges.All[float64]().Equal(r))
+
+	for _, want := range []granges.Range[float64]{
+		granges.Closed(-inf, inf),
+		granges.OpenClosed(0, inf),
+		granges.AtMost(inf),
+		granges.GreaterThan(-inf),
+	} {
+		r, err := granges.Parse(want.String(), parseFloat)
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(r), "Parse(%q) = %v, want %v", want.String(), r, want)
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		S       string
+		Mention string
+	}{
+		{S: "", Mention: "too short"},
+		{S: "<1..5]", Mention: "'<'"},
+		{S: "[1..5>", Mention: "'>'"},
+		{S: "[1,5]", Mention: "\"..\""},
+		{S: "[x..5]", Mention: "\"x\""},
+		{S: "[1..5y]", Mention: "\"5y\""},
+		{S: "[-∞..5]", Mention: "\"-∞\""},
+		{S: "(1..+inf]", Mention: "\"+inf\""},
+		{S: "(+∞..5)", Mention: "\"+∞\""},
+		{S: "(4..4)", Mention: "(4..4)"},
+		{S: "[5..4]", Mention: "[5..4]"},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.Parse(tt.S, strconv.Atoi)
+		if assert.Error(t, err, "Parse(%q)", tt.S) {
+			assert.Contains(t, err.Error(), tt.Mention)
+		}
+		assert.True(t, r.IsInvalid())
+	}
+
+	_, err := granges.Parse("[1..99999999999999999999]", strconv.Atoi)
+	assert.ErrorIs(t, err, strconv.ErrRange)
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		S    string
+		Want granges.Range[int]
+	}{
+		{S: "(1,5)", Want: granges.Open(1, 5)},
+		{S: "[1,5]", Want: granges.Closed(1, 5)},
+		{S: "[-1,5)", Want: granges.ClosedOpen(-1, 5)},
+		{S: "(1,5]", Want: granges.OpenClosed(1, 5)},
+		{S: "(,5)", Want: granges.LessThan(5)},
+		{S: "(,10]", Want: granges.AtMost(10)},
+		{S: "(-5,)", Want: granges.GreaterThan(-5)},
+		{S: "[5,)", Want: granges.AtLeast(5)},
+		{S: "(,)", Want: granges.All[int]()},
+		{S: "[3,3]", Want: granges.Singleton(3)},
+		{S: "[3,3)", Want: granges.ClosedOpen(3, 3)},
+		{S: " \t[ 1 , 5 )\n", Want: granges.ClosedOpen(1, 5)},
+		{S: "[,10]", Want: granges.AtMost(10)},
+		{S: "(-∞, +∞)", Want: granges.All[int]()},
+		{S: "[0, +inf]", Want: granges.AtLeast(0)},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.ParseInterval(tt.S, strconv.Atoi)
+		assert.NoError(t, err)
+		assert.True(t, tt.Want.Equal(r), "ParseInterval(%q) = %v, want %v", tt.S, r, tt.Want)
+	}
+
+	f, err := granges.ParseInterval("[0.5, 2.5)", func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+	assert.NoError(t, err)
+	assert.True(t, granges.ClosedOpen(0.5, 2.5).Equal(f))
+
+	// infinite floats are endpoints, as for Parse
+	f, err = granges.ParseInterval("[-inf,0]", func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+	assert.NoError(t, err)
+	assert.True(t, granges.Closed(math.Inf(-1), 0).Equal(f))
+}
+
+func TestParseInterval_errors(t *testing.T) {
+	tests := []struct {
+		S       string
+		Mention string
+	}{
+		{S: "", Mention: "too short"},
+		{S: "1,5]", Mention: "'1'"},
+		{S: "[1,5", Mention: "'5'"},
+		{S: "{1,5}", Mention: "'{'"},
+		{S: "[1..5]", Mention: "\",\""},
+		{S: "[x,5]", Mention: "\"x\""},
+		{S: "[1, 5y]", Mention: "\"5y\""},
+		{S: "[1,2,3]", Mention: "\"2,3\""},
+		{S: "(4,4)", Mention: "(4..4)"},
+		{S: "[5,4]", Mention: "[5..4]"},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.ParseInterval(tt.S, strconv.Atoi)
+		if assert.Error(t, err, "ParseInterval(%q)", tt.S) {
+			assert.Contains(t, err.Error(), tt.Mention)
+		}
+		assert.True(t, r.IsInvalid())
+	}
+}
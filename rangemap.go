@@ -0,0 +1,226 @@
+package granges
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RangeMap maps disjoint nonempty ranges of type C to values of type V.
+//
+// Putting a range overwrites the values of the entries it overlaps: the
+// overlapped portions of those entries are dropped, while their remaining
+// portions are kept with their original values, splitting an entry in two if
+// needed. Entries are never coalesced, even if connected entries hold the
+// same value.
+//
+// The zero value is an empty map ready to use. Every mutation replaces the
+// entries of the map instead of modifying them in place, thus a copy of a
+// RangeMap is never affected by mutations of the original.
+type RangeMap[C, V any] struct {
+	// entries holds the entries in ascending order, none of their ranges is
+	// empty and no two of them overlap.
+	entries []RangeMapEntry[C, V]
+}
+
+// RangeMapEntry is an entry of a RangeMap, mapping every value of Range to
+// Value.
+type RangeMapEntry[C, V any] struct {
+	Range Range[C]
+	Value V
+}
+
+// Put maps every value of r to value, overwriting the overlapped portions of
+// existing entries. Invalid and empty ranges are ignored, and so are ranges
+// ordered by another comparator than the entries of this map; see PutE.
+func (m *RangeMap[C, V]) Put(r Range[C], value V) {
+	_ = m.PutE(r, value)
+}
+
+// PutE maps every value of r to value, overwriting the overlapped portions of
+// existing entries. Invalid and empty ranges are ignored.
+//
+// ErrComparatorMismatch will be returned, leaving this map unchanged, if r is
+// ordered by another comparator than the entries of this map.
+func (m *RangeMap[C, V]) PutE(r Range[C], value V) error {
+	if r.IsInvalid() || r.IsEmpty() {
+		return nil
+	}
+	if err := m.checkOrder(r); err != nil {
+		return err
+	}
+	m.splice(r, RangeMapEntry[C, V]{Range: r, Value: value})
+	return nil
+}
+
+// Merge maps every value of r to value like Put does, except for the values
+// already mapped: the overlapped portions of existing entries are mapped to
+// combine(old, value) instead, where old is the value of the entry. The
+// portions of existing entries outside r are kept unchanged.
+//
+// For example, merging [1..10]=1 and then [5..15]=1 with addition yields
+// [1..5)=1, [5..10]=2, (10..15]=1. Invalid and empty ranges are ignored, and
+// so are ranges ordered by another comparator than the entries of this map;
+// see MergeE.
+func (m *RangeMap[C, V]) Merge(r Range[C], value V, combine func(old, new V) V) {
+	_ = m.MergeE(r, value, combine)
+}
+
+// MergeE maps every value of r to value like Merge does. Invalid and empty
+// ranges are ignored.
+//
+// ErrComparatorMismatch will be returned, leaving this map unchanged, if r is
+// ordered by another comparator than the entries of this map.
+func (m *RangeMap[C, V]) MergeE(r Range[C], value V, combine func(old, new V) V) error {
+	if r.IsInvalid() || r.IsEmpty() {
+		return nil
+	}
+	if err := m.checkOrder(r); err != nil {
+		return err
+	}
+
+	var merged []RangeMapEntry[C, V]
+	cursor := r.lowerBound
+	i, j := m.overlapping(r)
+	for _, e := range m.entries[i:j] {
+		overlap := e.Range.Intersection(r)
+		if cursor.Compare(overlap.lowerBound) < 0 {
+			gap := Range[C]{lowerBound: cursor, upperBound: overlap.lowerBound}
+			merged = append(merged, RangeMapEntry[C, V]{Range: gap, Value: value})
+		}
+		merged = append(merged, RangeMapEntry[C, V]{Range: overlap, Value: combine(e.Value, value)})
+		cursor = overlap.upperBound
+	}
+	if cursor.Compare(r.upperBound) < 0 {
+		rest := Range[C]{lowerBound: cursor, upperBound: r.upperBound}
+		merged = append(merged, RangeMapEntry[C, V]{Range: rest, Value: value})
+	}
+	m.splice(r, merged...)
+	return nil
+}
+
+// Remove removes the mappings of every value of r, truncating or splitting
+// the entries overlapping r. Invalid and empty ranges are ignored, and so are
+// ranges ordered by another comparator than the entries of this map; see
+// RemoveE.
+func (m *RangeMap[C, V]) Remove(r Range[C]) {
+	_ = m.RemoveE(r)
+}
+
+// RemoveE removes the mappings of every value of r, truncating or splitting
+// the entries overlapping r. Invalid and empty ranges are ignored.
+//
+// ErrComparatorMismatch will be returned, leaving this map unchanged, if r is
+// ordered by another comparator than the entries of this map.
+func (m *RangeMap[C, V]) RemoveE(r Range[C]) error {
+	if r.IsInvalid() || r.IsEmpty() {
+		return nil
+	}
+	if err := m.checkOrder(r); err != nil {
+		return err
+	}
+	m.splice(r)
+	return nil
+}
+
+// Get returns the value mapped to key, and true if key is contained in the
+// range of some entry.
+func (m RangeMap[C, V]) Get(key C) (V, bool) {
+	// the first entry whose upper bound is not below key
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return !m.entries[i].Range.upperBound.IsLessThan(key)
+	})
+	if i < len(m.entries) && m.entries[i].Range.Contains(key) {
+		return m.entries[i].Value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// IsEmpty returns true if this map has no entries.
+func (m RangeMap[C, V]) IsEmpty() bool {
+	return len(m.entries) == 0
+}
+
+// AsMapOfRanges returns the entries of this map in ascending order of their
+// ranges. The ranges are disjoint and nonempty, but connected entries holding
+// the same value are not merged, so [1..2]=x, (2..3]=x stay two entries.
+func (m RangeMap[C, V]) AsMapOfRanges() []RangeMapEntry[C, V] {
+	return slices.Clone(m.entries)
+}
+
+func (m RangeMap[C, V]) String() string {
+	entries := make([]string, len(m.entries))
+	for i, e := range m.entries {
+		entries[i] = fmt.Sprintf("%s=%v", e.Range, e.Value)
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// checkOrder returns an error if r may not be combined with the entries of
+// this map because they are not ordered the same way.
+func (m RangeMap[C, V]) checkOrder(r Range[C]) error {
+	if len(m.entries) == 0 || sameOrder(m.entries[0].Range.order(), r.order()) {
+		return nil
+	}
+	return fmt.Errorf("cannot combine %s with %s: %w", r, m, ErrComparatorMismatch)
+}
+
+// splice drops the mappings of every value of the nonempty range r, and puts
+// the given entries in their place. The inserted entries must be sorted and
+// lie within r.
+func (m *RangeMap[C, V]) splice(r Range[C], inserted ...RangeMapEntry[C, V]) {
+	i, j := m.overlapping(r)
+	var below, above []RangeMapEntry[C, V]
+	if i < j {
+		if first := m.entries[i]; first.Range.lowerBound.Compare(r.lowerBound) < 0 {
+			first.Range = Range[C]{lowerBound: first.Range.lowerBound, upperBound: r.lowerBound}
+			below = append(below, first)
+		}
+		if last := m.entries[j-1]; r.upperBound.Compare(last.Range.upperBound) < 0 {
+			last.Range = Range[C]{lowerBound: r.upperBound, upperBound: last.Range.upperBound}
+			above = append(above, last)
+		}
+	}
+	m.entries = slices.Concat(m.entries[:i], below, inserted, above, m.entries[j:])
+}
+
+// overlapping returns the indices [i, j) of the entries having a nonempty
+// intersection with the nonempty range r.
+func (m RangeMap[C, V]) overlapping(r Range[C]) (i, j int) {
+	i = sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].Range.upperBound.Compare(r.lowerBound) > 0
+	})
+	j = sort.Search(len(m.entries), func(j int) bool {
+		return m.entries[j].Range.lowerBound.Compare(r.upperBound) >= 0
+	})
+	return i, j
+}
+
+// InvertRangeMap returns the ranges mapped by m to each key of its values,
+// where keyOf(value) is the key of a value: the set of a key holds every
+// value of C that m maps to a value having that key. Connected entries whose
+// values share a key are coalesced, so that the entries of an owner split in
+// two by a later Put, such as [1..3]=a, (3..5)=b, [5..10]=a, come out as the
+// set {[1..3], [5..10]} for a and {(3..5)} for b, and come back together as
+// {[1..10]} once b is overwritten with a.
+//
+// The index is built in a single pass over the entries of m, which is left
+// unchanged.
+func InvertRangeMap[C, V any, K comparable](m RangeMap[C, V], keyOf func(V) K) map[K]RangeSet[C] {
+	index := make(map[K]RangeSet[C])
+	for _, e := range m.entries {
+		key := keyOf(e.Value)
+		s := index[key]
+		// the entries come in ascending order, so each one may only be
+		// connected to the last member of the set of its key
+		if last := len(s.ranges) - 1; last >= 0 && s.ranges[last].upperBound.Compare(e.Range.lowerBound) >= 0 {
+			s.ranges[last].upperBound = e.Range.upperBound
+		} else {
+			s.ranges = append(s.ranges, e.Range)
+		}
+		index[key] = s
+	}
+	return index
+}
@@ -0,0 +1,231 @@
+package granges_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestRangeMap_Put(t *testing.T) {
+	var m granges.RangeMap[int, string]
+	assert.True(t, m.IsEmpty())
+	assert.EqualValues(t, "{}", m.String())
+
+	m.Put(granges.Closed(1, 10), "a")
+	assert.EqualValues(t, "{[1..10]=a}", m.String())
+
+	// splitting an entry in two
+	m.Put(granges.Open(3, 5), "b")
+	assert.EqualValues(t, "{[1..3]=a, (3..5)=b, [5..10]=a}", m.String())
+
+	// overwriting several entries
+	m.Put(granges.Closed(2, 6), "c")
+	assert.EqualValues(t, "{[1..2)=a, [2..6]=c, (6..10]=a}", m.String())
+
+	// connected entries are not coalesced, even with the same value
+	m.Put(granges.OpenClosed(10, 12), "a")
+	m.Put(granges.LessThan(1), "d")
+	assert.EqualValues(t, "{(-∞..1)=d, [1..2)=a, [2..6]=c, (6..10]=a, (10..12]=a}", m.String())
+
+	// overwriting an entry exactly
+	m.Put(granges.Closed(2, 6), "e")
+	assert.EqualValues(t, "{(-∞..1)=d, [1..2)=a, [2..6]=e, (6..10]=a, (10..12]=a}", m.String())
+
+	// empty and invalid ranges are ignored
+	m.Put(granges.ClosedOpen(4, 4), "x")
+	m.Put(granges.Invalid[int](), "x")
+	assert.EqualValues(t, "{(-∞..1)=d, [1..2)=a, [2..6]=e, (6..10]=a, (10..12]=a}", m.String())
+
+	m.Put(granges.All[int](), "f")
+	assert.EqualValues(t, "{(-∞..+∞)=f}", m.String())
+}
+
+func TestRangeMap_comparatorMismatch(t *testing.T) {
+	var m granges.RangeMap[time.Time, int]
+	assert.NoError(t, m.PutE(byTime.ClosedOpen(day(0), day(3)), 1))
+
+	// a range ordered naturally is not combined with entries ordered by a
+	// comparator
+	assert.ErrorIs(t, m.PutE(granges.Closed(day(1), day(3)), 2), granges.ErrComparatorMismatch)
+	assert.ErrorIs(t, m.MergeE(granges.Closed(day(1), day(3)), 2, sum), granges.ErrComparatorMismatch)
+	assert.ErrorIs(t, m.RemoveE(granges.Closed(day(1), day(3))), granges.ErrComparatorMismatch)
+	m.Put(granges.Closed(day(1), day(3)), 2)
+	m.Merge(granges.Closed(day(1), day(3)), 2, sum)
+	m.Remove(granges.Closed(day(1), day(3)))
+	assert.EqualValues(t, []granges.RangeMapEntry[time.Time, int]{
+		{Range: byTime.ClosedOpen(day(0), day(3)), Value: 1},
+	}, m.AsMapOfRanges())
+
+	assert.NoError(t, m.MergeE(byTime.Closed(day(2), day(4)), 2, sum))
+	assert.NoError(t, m.PutE(byTime.ClosedOpen(day(0), day(1)), 5))
+	assert.NoError(t, m.RemoveE(byTime.Singleton(day(4))))
+	assert.EqualValues(t, []granges.RangeMapEntry[time.Time, int]{
+		{Range: byTime.ClosedOpen(day(0), day(1)), Value: 5},
+		{Range: byTime.ClosedOpen(day(1), day(2)), Value: 1},
+		{Range: byTime.ClosedOpen(day(2), day(3)), Value: 3},
+		{Range: byTime.ClosedOpen(day(3), day(4)), Value: 2},
+	}, m.AsMapOfRanges())
+}
+
+func sum(a, b int) int {
+	return a + b
+}
+
+func TestRangeMap_Get(t *testing.T) {
+	var m granges.RangeMap[int, string]
+	m.Put(granges.ClosedOpen(1, 3), "a")
+	m.Put(granges.Closed(3, 5), "b")
+	m.Put(granges.OpenClosed(5, 7), "c")
+	m.Put(granges.GreaterThan(10), "d")
+
+	for key, want := range map[int]string{
+		0: "", 1: "a", 2: "a", 3: "b", 5: "b", 6: "c", 7: "c", 8: "", 10: "", 11: "d",
+	} {
+		v, ok := m.Get(key)
+		assert.EqualValues(t, want != "", ok, "Get(%d)", key)
+		assert.EqualValues(t, want, v, "Get(%d)", key)
+	}
+
+	var empty granges.RangeMap[int, string]
+	_, ok := empty.Get(0)
+	assert.False(t, ok)
+}
+
+func TestRangeMap_Remove(t *testing.T) {
+	var m granges.RangeMap[int, int]
+	m.Put(granges.Closed(1, 10), 1)
+	m.Put(granges.Closed(20, 30), 2)
+
+	m.Remove(granges.Closed(3, 5))
+	assert.EqualValues(t, "{[1..3)=1, (5..10]=1, [20..30]=2}", m.String())
+
+	m.Remove(granges.Open(8, 25))
+	assert.EqualValues(t, "{[1..3)=1, (5..8]=1, [25..30]=2}", m.String())
+
+	// removing what is not there changes nothing
+	m.Remove(granges.ClosedOpen(3, 5))
+	m.Remove(granges.Closed(40, 50))
+	m.Remove(granges.ClosedOpen(7, 7))
+	assert.EqualValues(t, "{[1..3)=1, (5..8]=1, [25..30]=2}", m.String())
+
+	m.Remove(granges.AtMost(6))
+	assert.EqualValues(t, "{(6..8]=1, [25..30]=2}", m.String())
+
+	m.Remove(granges.All[int]())
+	assert.True(t, m.IsEmpty())
+}
+
+func TestRangeMap_copy(t *testing.T) {
+	var m granges.RangeMap[int, string]
+	m.Put(granges.Closed(1, 10), "a")
+
+	cp := m
+	cp.Put(granges.Closed(3, 5), "b")
+	cp.Remove(granges.Singleton(1))
+	assert.EqualValues(t, "{[1..10]=a}", m.String())
+	assert.EqualValues(t, "{(1..3)=a, [3..5]=b, (5..10]=a}", cp.String())
+}
+
+func TestRangeMap_AsMapOfRanges(t *testing.T) {
+	var m granges.RangeMap[int, string]
+	m.Put(granges.Closed(5, 10), "b")
+	m.Put(granges.Closed(1, 2), "x")
+	m.Put(granges.OpenClosed(2, 3), "x")
+	m.Put(granges.Open(6, 8), "c")
+
+	entries := m.AsMapOfRanges()
+	want := []granges.RangeMapEntry[int, string]{
+		{Range: granges.Closed(1, 2), Value: "x"},
+		{Range: granges.OpenClosed(2, 3), Value: "x"},
+		{Range: granges.Closed(5, 6), Value: "b"},
+		{Range: granges.Open(6, 8), Value: "c"},
+		{Range: granges.Closed(8, 10), Value: "b"},
+	}
+	if assert.Len(t, entries, len(want)) {
+		for i := range want {
+			assert.True(t, want[i].Range.Equal(entries[i].Range), "entry %d range = %v, want %v", i, entries[i].Range, want[i].Range)
+			assert.EqualValues(t, want[i].Value, entries[i].Value)
+		}
+	}
+
+	// the returned slice is a copy
+	entries[0].Value = "changed"
+	v, _ := m.Get(1)
+	assert.EqualValues(t, "x", v)
+
+	var empty granges.RangeMap[int, string]
+	assert.Empty(t, empty.AsMapOfRanges())
+}
+
+func TestRangeMap_Merge(t *testing.T) {
+	sum := func(old, new int) int { return old + new }
+
+	var m granges.RangeMap[int, int]
+	m.Merge(granges.Closed(1, 10), 1, sum)
+	m.Merge(granges.Closed(5, 15), 1, sum)
+	assert.EqualValues(t, "{[1..5)=1, [5..10]=2, (10..15]=1}", m.String())
+
+	// filling the gaps between entries with the raw value
+	m.Merge(granges.Open(20, 30), 5, sum)
+	m.Merge(granges.Closed(0, 25), 10, sum)
+	assert.EqualValues(t, "{[0..1)=10, [1..5)=11, [5..10]=12, (10..15]=11, (15..20]=10, (20..25]=15, (25..30)=5}", m.String())
+
+	// merging within a single entry splits it
+	m.Merge(granges.Singleton(27), 100, sum)
+	assert.EqualValues(t, "{[0..1)=10, [1..5)=11, [5..10]=12, (10..15]=11, (15..20]=10, (20..25]=15, (25..27)=5, [27..27]=105, (27..30)=5}", m.String())
+
+	// empty and invalid ranges are ignored
+	m.Merge(granges.ClosedOpen(3, 3), 1, sum)
+	m.Merge(granges.Invalid[int](), 1, sum)
+	assert.EqualValues(t, "{[0..1)=10, [1..5)=11, [5..10]=12, (10..15]=11, (15..20]=10, (20..25]=15, (25..27)=5, [27..27]=105, (27..30)=5}", m.String())
+
+	var tags granges.RangeMap[int, []string]
+	tags.Put(granges.Closed(1, 5), []string{"a"})
+	tags.Merge(granges.AtLeast(3), []string{"b"}, func(old, new []string) []string {
+		return append(append([]string{}, old...), new...)
+	})
+	assert.EqualValues(t, "{[1..3)=[a], [3..5]=[a b], (5..+∞)=[b]}", tags.String())
+}
+
+func TestInvertRangeMap(t *testing.T) {
+	type shard struct {
+		Node    string
+		Replica int
+	}
+	node := func(s shard) string { return s.Node }
+
+	var owners granges.RangeMap[int, shard]
+	owners.Put(granges.ClosedOpen(0, 100), shard{Node: "a", Replica: 1})
+	owners.Put(granges.ClosedOpen(100, 200), shard{Node: "b", Replica: 1})
+	owners.Put(granges.ClosedOpen(200, 300), shard{Node: "a", Replica: 2})
+
+	index := granges.InvertRangeMap(owners, node)
+	assert.Len(t, index, 2)
+	assert.EqualValues(t, "{[0..100), [200..300)}", index["a"].String())
+	assert.EqualValues(t, "{[100..200)}", index["b"].String())
+
+	// entries split by a later Put
+	owners.Put(granges.ClosedOpen(40, 60), shard{Node: "c"})
+	index = granges.InvertRangeMap(owners, node)
+	assert.Len(t, index, 3)
+	assert.EqualValues(t, "{[0..40), [60..100), [200..300)}", index["a"].String())
+	assert.EqualValues(t, "{[40..60)}", index["c"].String())
+
+	// connected entries of the same key are coalesced, whatever their values
+	owners.Put(granges.ClosedOpen(40, 60), shard{Node: "a", Replica: 3})
+	owners.Put(granges.ClosedOpen(100, 200), shard{Node: "a", Replica: 4})
+	assert.Len(t, owners.AsMapOfRanges(), 5)
+	index = granges.InvertRangeMap(owners, node)
+	assert.Len(t, index, 1)
+	assert.EqualValues(t, "{[0..300)}", index["a"].String())
+
+	// keyed by the whole value, entries of distinct replicas stay apart
+	byShard := granges.InvertRangeMap(owners, func(s shard) shard { return s })
+	assert.Len(t, byShard, 4)
+	assert.EqualValues(t, "{[0..40), [60..100)}", byShard[shard{Node: "a", Replica: 1}].String())
+
+	assert.Empty(t, granges.InvertRangeMap(granges.RangeMap[int, shard]{}, node))
+}
@@ -1,15 +1,23 @@
 package granges
 
-import "fmt"
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+)
 
-type Range[C Comparable] struct {
+type Range[C any] struct {
 	lowerBound Cut[C]
 	upperBound Cut[C]
 
 	invalid bool
 }
 
-func create[C Comparable](lowerBound, upperBound Cut[C]) (Range[C], error) {
+func create[C any](lowerBound, upperBound Cut[C]) (Range[C], error) {
 	if lowerBound.Compare(upperBound) > 0 ||
 		lowerBound.cutType == AboveAll ||
 		upperBound.cutType == BelowAll {
@@ -108,11 +116,92 @@ func (r Range[C]) IsEmpty() bool {
 	return r.lowerBound.Compare(r.upperBound) == 0
 }
 
+// IsAll returns true if this range is (-∞..+∞), containing every value.
+func (r Range[C]) IsAll() bool {
+	return !r.IsInvalid() && !r.HasLowerBound() && !r.HasUpperBound()
+}
+
+// IsBounded returns true if this range has both a lower and an upper
+// endpoint, such as [1..5) or the empty [4..4).
+func (r Range[C]) IsBounded() bool {
+	return !r.IsInvalid() && r.HasLowerBound() && r.HasUpperBound()
+}
+
+// IsUnbounded returns true if this range is unbounded on at least one side,
+// such as [1..+∞) or (-∞..+∞). For valid ranges, it is the negation of
+// IsBounded, while the invalid range is neither bounded nor unbounded.
+func (r Range[C]) IsUnbounded() bool {
+	return !r.IsInvalid() && !r.IsBounded()
+}
+
+// IsSingleton returns true if this range is of the form [v..v], containing
+// exactly the value v. Ranges holding a single value of a discrete domain
+// without being closed on it, such as [4..5) in the integers, are not
+// singletons; see Canonical.
+func (r Range[C]) IsSingleton() bool {
+	_, ok := r.SingletonValue()
+	return ok
+}
+
+// SingletonValue returns the only value contained in this range if it is of
+// the form [v..v], or false otherwise; see IsSingleton.
+func (r Range[C]) SingletonValue() (C, bool) {
+	if r.IsInvalid() || r.lowerBound.cutType != BelowValue {
+		var zero C
+		return zero, false
+	}
+	value := r.lowerBound.endpoint
+	if r.upperBound.Compare(r.lowerBound.aboveValue(value)) != 0 {
+		var zero C
+		return zero, false
+	}
+	return value, true
+}
+
 // Contains returns true if value is within the bounds of this range. For
 // example, on the range [0..2), Contains(1) returns true, while Contains(2)
 // returns false.
 func (r Range[C]) Contains(value C) bool {
-	return r.lowerBound.IsLessThan(value) && !r.upperBound.IsLessThan(value)
+	// this is r.lowerBound.IsLessThan(value) && !r.upperBound.IsLessThan(value)
+	// spelled out, so that the endpoints are compared without a call
+	lower, upper := r.lowerBound, r.upperBound
+	if lower.cutType == AboveAll || upper.cutType == BelowAll {
+		return false
+	}
+	if lower.cutType != BelowAll {
+		n := lower.orderWith(nil).compare(lower.endpoint, value)
+		if n > 0 || n == 0 && lower.cutType == AboveValue {
+			return false
+		}
+	}
+	if upper.cutType != AboveAll {
+		n := upper.orderWith(nil).compare(upper.endpoint, value)
+		if n < 0 || n == 0 && upper.cutType == BelowValue {
+			return false
+		}
+	}
+	return true
+}
+
+// AsPredicate returns a predicate reporting whether a value is contained in
+// this range, as Contains does, for APIs taking filters such as
+// slices.IndexFunc. The predicate of the invalid range is always false.
+func (r Range[C]) AsPredicate() func(C) bool {
+	if r.IsInvalid() {
+		return func(C) bool { return false }
+	}
+	return r.Contains
+}
+
+// NotPredicate returns the negation of AsPredicate, reporting whether a value
+// is not contained in this range. It keeps the values of this range when
+// given to slices.DeleteFunc. The predicate of the invalid range is always
+// true.
+func (r Range[C]) NotPredicate() func(C) bool {
+	contains := r.AsPredicate()
+	return func(value C) bool {
+		return !contains(value)
+	}
 }
 
 // ContainsAll returns true if every element in values is contained in this
@@ -129,6 +218,143 @@ func (r Range[C]) ContainsAll(values []C) bool {
 	return true
 }
 
+// ContainsAllSeq returns true if every value yielded by seq is contained in
+// this range. The sequence is consumed lazily and no further values are
+// pulled once a value outside this range is found. It returns true for an
+// empty sequence.
+func (r Range[C]) ContainsAllSeq(seq iter.Seq[C]) bool {
+	for value := range seq {
+		if !r.Contains(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAnySeq returns true if some value yielded by seq is contained in
+// this range. The sequence is consumed lazily and no further values are
+// pulled once a value inside this range is found. It returns false for an
+// empty sequence.
+func (r Range[C]) ContainsAnySeq(seq iter.Seq[C]) bool {
+	for value := range seq {
+		if r.Contains(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsRange returns true if every value contained in other is also
+// contained in this range. Unlike Encloses, which compares bounds, it reasons
+// about the values the ranges actually hold: any range holding no value is
+// contained in every valid range, and the ranges of integer types are
+// compared by their smallest and largest values, so that
+//
+//   - [4..5] contains (3..6), though it does not enclose it
+//   - [3..6] contains (1..1], though it does not enclose it
+//   - [3..6] contains (5..6), as it holds no integer
+//   - [4..5] does not contain (3.0..6.0), which holds 3.5
+//
+// Encloses implies ContainsRange, and r.ContainsRange(Singleton(v)) is the
+// same as r.Contains(v). It returns false if either range is invalid.
+func (r Range[C]) ContainsRange(other Range[C]) bool {
+	if r.IsInvalid() || other.IsInvalid() {
+		return false
+	}
+	otherLower, otherUpper := other.valueCuts()
+	if otherLower.Compare(otherUpper) >= 0 {
+		return true
+	}
+	lower, upper := r.valueCuts()
+	return lower.Compare(otherLower) <= 0 && upper.Compare(otherUpper) >= 0
+}
+
+// valueCuts returns the cuts tightest around the values of this range. For
+// ranges of integer types, open endpoints are replaced with the closed
+// endpoints next to them, so that (3..6) yields the cuts of [4..5], and
+// unbounded sides are closed at the limits of the type; a lower cut not below
+// its upper cut means that the range holds no value. Other ranges yield their
+// own bounds.
+func (r Range[C]) valueCuts() (lower, upper Cut[C]) {
+	lower, upper = r.lowerBound, r.upperBound
+	// stepping to adjacent integers only makes sense in their natural order
+	integer := isInteger[C]() && isNatural(r.order())
+	if integer {
+		minValue, maxValue := integerLimits[C]()
+		if lower.cutType == BelowAll {
+			lower = lower.belowValue(minValue)
+		}
+		if upper.cutType == AboveAll {
+			upper = upper.aboveValue(maxValue)
+		}
+	}
+	if lower.cutType == AboveValue && integer {
+		if next, ok := stepInteger(lower.endpoint, 1); ok {
+			lower = lower.belowValue(next)
+		} else {
+			lower = lower.aboveAll()
+		}
+	}
+	if upper.cutType == BelowValue && integer {
+		if previous, ok := stepInteger(upper.endpoint, -1); ok {
+			upper = upper.aboveValue(previous)
+		} else {
+			upper = upper.belowAll()
+		}
+	}
+	return lower, upper
+}
+
+// isInteger returns true if the underlying type of C is an integer type.
+func isInteger[C any]() bool {
+	var zero C
+	switch reflect.TypeOf(zero).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// integerLimits returns the smallest and largest values of C, whose
+// underlying type must be an integer type.
+func integerLimits[C any]() (minValue, maxValue C) {
+	lower := reflect.ValueOf(&minValue).Elem()
+	upper := reflect.ValueOf(&maxValue).Elem()
+	bits := lower.Type().Bits()
+	if lower.CanInt() {
+		lower.SetInt(-1 << (bits - 1))
+		upper.SetInt(1<<(bits-1) - 1)
+	} else {
+		upper.SetUint(math.MaxUint64 >> (64 - bits))
+	}
+	return minValue, maxValue
+}
+
+// stepInteger returns value + delta, where delta is 1 or -1, if the
+// underlying type of C is an integer type and the result fits in C.
+func stepInteger[C any](value C, delta int64) (C, bool) {
+	v := reflect.ValueOf(&value).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if delta > 0 && i == math.MaxInt64 || delta < 0 && i == math.MinInt64 || v.OverflowInt(i+delta) {
+			return value, false
+		}
+		v.SetInt(i + delta)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if delta > 0 && (u == math.MaxUint64 || v.OverflowUint(u+1)) || delta < 0 && u == 0 {
+			return value, false
+		}
+		v.SetUint(u + uint64(delta))
+	default:
+		return value, false
+	}
+	return value, true
+}
+
 // Encloses returns true if the bounds of other do not extend outside the
 // bounds of this range.
 //
@@ -148,9 +374,11 @@ func (r Range[C]) ContainsAll(values []C) bool {
 // Being reflexive, antisymmetric and transitive, the encloses relation defines
 // a partial order over ranges. There exists a unique maximal range according
 // to this relation, and also numerous minimal ranges. Enclosure also implies
-// connectedness.
+// connectedness. Ranges ordered by different comparators never enclose each
+// other.
 func (r Range[C]) Encloses(other Range[C]) bool {
-	return r.lowerBound.Compare(other.lowerBound) <= 0 &&
+	return sameOrder(r.order(), other.order()) &&
+		r.lowerBound.Compare(other.lowerBound) <= 0 &&
 		r.upperBound.Compare(other.upperBound) >= 0
 }
 
@@ -168,12 +396,146 @@ func (r Range[C]) Encloses(other Range[C]) bool {
 // (as a single, possibly-empty range) if and only if this method returns true.
 //
 // The connectedness relation is both reflexive and symmetric, but does not
-// form an equivalence relation as it is not transitive.
+// form an equivalence relation as it is not transitive. Ranges ordered by
+// different comparators are never connected.
 func (r Range[C]) IsConnected(other Range[C]) bool {
-	return r.lowerBound.Compare(other.upperBound) <= 0 &&
+	return sameOrder(r.order(), other.order()) &&
+		r.lowerBound.Compare(other.upperBound) <= 0 &&
 		other.lowerBound.Compare(r.upperBound) <= 0
 }
 
+// Intersects returns true if this range and other share at least one value,
+// that is if they are connected and their intersection is not empty.
+//
+// For example,
+//
+//   - [2..4) and [3..5) intersect, as both contain the values in [3..4)
+//   - [2..4) and [4..6) do not intersect, even though they are connected
+//   - [4..4) intersects no range, not even [2..6)
+func (r Range[C]) Intersects(other Range[C]) bool {
+	if r.IsInvalid() || other.IsInvalid() || r.IsEmpty() || other.IsEmpty() ||
+		!sameOrder(r.order(), other.order()) {
+		return false
+	}
+	return r.lowerBound.Compare(other.upperBound) < 0 &&
+		other.lowerBound.Compare(r.upperBound) < 0
+}
+
+// EnclosesAll returns true if this range encloses every range in rs. It
+// returns true for an empty slice.
+func (r Range[C]) EnclosesAll(rs []Range[C]) bool {
+	for i := range rs {
+		if !r.Encloses(rs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// EnclosesAny returns true if this range encloses at least one range in rs.
+// It returns false for an empty slice.
+func (r Range[C]) EnclosesAny(rs []Range[C]) bool {
+	for i := range rs {
+		if r.Encloses(rs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsConnectedToAny returns the index of the first range in rs which is
+// connected to this range, and true if there is such a range.
+func (r Range[C]) IsConnectedToAny(rs []Range[C]) (int, bool) {
+	for i := range rs {
+		if r.IsConnected(rs[i]) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// IsConnectedToAnySorted is like IsConnectedToAny, but finds the range with a
+// binary search. The ranges in rs must be sorted in ascending order and
+// pairwise disjoint, such as the ranges of a canonical set; the result is
+// undefined otherwise.
+func (r Range[C]) IsConnectedToAnySorted(rs []Range[C]) (int, bool) {
+	i := sort.Search(len(rs), func(i int) bool {
+		return rs[i].upperBound.Compare(r.lowerBound) >= 0
+	})
+	if i < len(rs) && rs[i].lowerBound.Compare(r.upperBound) <= 0 {
+		return i, true
+	}
+	return -1, false
+}
+
+// Complement returns the ranges containing every value not contained in this
+// range, in ascending order: the range below the lower bound of this range
+// and the range above its upper bound, for those sides which are bounded.
+//
+// For example, the complement of [3..8] is (-∞..3) and (8..+∞), the
+// complement of (-∞..5) is [5..+∞), and the complement of All is empty. An
+// empty range such as [4..4) yields (-∞..4) and [4..+∞), which together are
+// All. Nil is returned for invalid ranges.
+func (r Range[C]) Complement() []Range[C] {
+	if r.IsInvalid() {
+		return nil
+	}
+	complement := make([]Range[C], 0, 2)
+	if r.HasLowerBound() {
+		complement = append(complement, Range[C]{lowerBound: r.lowerBound.belowAll(), upperBound: r.lowerBound})
+	}
+	if r.HasUpperBound() {
+		complement = append(complement, Range[C]{lowerBound: r.upperBound, upperBound: r.upperBound.aboveAll()})
+	}
+	return complement
+}
+
+// Difference returns the ranges containing every value of this range not
+// contained in other, in ascending order. Bound types are flipped at the
+// cuts, so that no value is lost or shared between the results and other.
+//
+// For example, [1..10] minus [3..5] is [1..3) and (5..10], [1..10] minus
+// [5..+∞) is [1..5), and [3..5] minus [1..10] is empty. This range is returned
+// unchanged if it does not intersect other. Empty ranges are never part of
+// the result, and nil is returned if either range is invalid.
+func (r Range[C]) Difference(other Range[C]) []Range[C] {
+	if r.IsInvalid() || other.IsInvalid() {
+		return nil
+	}
+	if r.IsEmpty() {
+		return []Range[C]{}
+	}
+	if !r.Intersects(other) {
+		return []Range[C]{r}
+	}
+	difference := make([]Range[C], 0, 2)
+	if r.lowerBound.Compare(other.lowerBound) < 0 {
+		difference = append(difference, Range[C]{lowerBound: r.lowerBound, upperBound: other.lowerBound})
+	}
+	if other.upperBound.Compare(r.upperBound) < 0 {
+		difference = append(difference, Range[C]{lowerBound: other.upperBound, upperBound: r.upperBound})
+	}
+	return difference
+}
+
+// SymmetricDifference returns the ranges containing every value contained in
+// exactly one of this range and other, in ascending order.
+//
+// For example, the symmetric difference of [1..5] and [3..7] is [1..3) and
+// (5..7], the one of two disjoint ranges is both ranges, and the one of two
+// equal ranges is empty. Empty ranges are never part of the result, and nil
+// is returned if either range is invalid.
+func (r Range[C]) SymmetricDifference(other Range[C]) []Range[C] {
+	if r.IsInvalid() || other.IsInvalid() {
+		return nil
+	}
+	difference := slices.Concat(r.Difference(other), other.Difference(r))
+	slices.SortFunc(difference, func(a, b Range[C]) int {
+		return a.lowerBound.Compare(b.lowerBound)
+	})
+	return difference
+}
+
 // Intersection returns the maximal range enclosed by both this range and
 // connectedRange, if such a range exists.
 //
@@ -195,8 +557,12 @@ func (r Range[C]) Intersection(connectedRange Range[C]) Range[C] {
 // The intersection operation is commutative, associative and idempotent, and
 // its identity element is All.
 //
-// An error will be returned for disconnected ranges.
+// An error will be returned for disconnected ranges, and ErrComparatorMismatch
+// for ranges ordered by different comparators.
 func (r Range[C]) IntersectionE(connectedRange Range[C]) (Range[C], error) {
+	if err := r.checkOrder(connectedRange); err != nil {
+		return Invalid[C](), err
+	}
 	lowerCmp := r.lowerBound.Compare(connectedRange.lowerBound)
 	upperCmp := r.upperBound.Compare(connectedRange.upperBound)
 	if lowerCmp >= 0 && upperCmp <= 0 {
@@ -231,6 +597,64 @@ func (r Range[C]) IntersectionE(connectedRange Range[C]) (Range[C], error) {
 	}
 }
 
+// SplitAt splits this range at point into the portion below point and the
+// portion above it, where boundType tells which portion point goes to: OPEN
+// leaves point out of the lower portion and in the upper one, while CLOSED
+// puts it in the lower portion. For example, [0..10] split at 4 yields [0..4)
+// and [4..10] with OPEN, and [0..4] and (4..10] with CLOSED.
+//
+// Splitting at a closed endpoint yields an empty portion on the side of that
+// endpoint, such as [0..0) and [0..10] for [0..10] split at 0 with OPEN.
+//
+// Invalid ranges with an error will be returned if this range is invalid, if
+// it does not contain point, or ErrWrongBoundType if boundType is neither
+// OPEN nor CLOSED.
+func (r Range[C]) SplitAt(point C, boundType BoundType) (Range[C], Range[C], error) {
+	if r.IsInvalid() {
+		return Invalid[C](), Invalid[C](), fmt.Errorf("cannot split invalid range")
+	}
+	var cut Cut[C]
+	switch boundType {
+	case OPEN:
+		cut = r.lowerBound.belowValue(point)
+	case CLOSED:
+		cut = r.lowerBound.aboveValue(point)
+	default:
+		return Invalid[C](), Invalid[C](), ErrWrongBoundType
+	}
+	if !r.Contains(point) {
+		return Invalid[C](), Invalid[C](), fmt.Errorf("cannot split %s at %v outside the range", r, point)
+	}
+	return Range[C]{lowerBound: r.lowerBound, upperBound: cut}, Range[C]{lowerBound: cut, upperBound: r.upperBound}, nil
+}
+
+// ClampTo returns this range clipped to window. Unlike Intersection, clipping
+// always succeeds for valid ranges: the intersection is returned when the
+// two ranges are connected, otherwise an empty range sitting at the edge of
+// window nearest to this range is returned.
+//
+// For example, clipping [-10..50] to [0..30] yields [0..30]. Clipping [40..50]
+// to [0..30] yields the empty range [30..30) at the upper bound of window,
+// and clipping [-20..-10] yields the empty range [0..0) at its lower bound.
+// The empty range is always of the form [v..v), except at a lower bound of
+// window open on v, such as (0..30], where it is (v..v] so as to remain
+// enclosed by window.
+//
+// An invalid range will be returned if either range is invalid.
+func (r Range[C]) ClampTo(window Range[C]) Range[C] {
+	if r.IsInvalid() || window.IsInvalid() {
+		return Invalid[C]()
+	}
+	if r.upperBound.Compare(window.lowerBound) < 0 {
+		return Range[C]{lowerBound: window.lowerBound, upperBound: window.lowerBound}
+	}
+	if r.lowerBound.Compare(window.upperBound) > 0 {
+		edge := window.upperBound.belowValue(window.upperBound.endpoint)
+		return Range[C]{lowerBound: edge, upperBound: edge}
+	}
+	return r.Intersection(window)
+}
+
 // Gap returns the maximal range lying between this range and otherRange, if
 // such a range exists. The resulting range may be empty if the two ranges are
 // adjacent but non-overlapping.
@@ -256,8 +680,12 @@ func (r Range[C]) Gap(other Range[C]) Range[C] {
 // The gap operation is commutative.
 //
 // An error will be returned if this range and otherRange have a nonempty
-// intersection.
+// intersection, and ErrComparatorMismatch if they are ordered by different
+// comparators.
 func (r Range[C]) GapE(other Range[C]) (Range[C], error) {
+	if err := r.checkOrder(other); err != nil {
+		return Invalid[C](), err
+	}
 	if r.lowerBound.Compare(other.upperBound) < 0 &&
 		other.lowerBound.Compare(r.upperBound) < 0 {
 		return Invalid[C](), fmt.Errorf(
@@ -297,8 +725,12 @@ func (r Range[C]) Span(other Range[C]) Range[C] {
 // Like intersection, this operation is commutative, associative and
 // idempotent. Unlike it, it is always well-defined for any two input ranges.
 //
-// An error will be returned if failed to create new range.
+// An error will be returned if failed to create new range, such as
+// ErrComparatorMismatch for ranges ordered by different comparators.
 func (r Range[C]) SpanE(other Range[C]) (Range[C], error) {
+	if err := r.checkOrder(other); err != nil {
+		return Invalid[C](), err
+	}
 	lowerCmp := r.lowerBound.Compare(other.lowerBound)
 	upperCmp := r.upperBound.Compare(other.upperBound)
 	if lowerCmp <= 0 && upperCmp >= 0 {
@@ -324,6 +756,265 @@ func (r Range[C]) SpanE(other Range[C]) (Range[C], error) {
 	}
 }
 
+// Union returns the range containing every value of both this range and
+// connectedRange, if such a range exists.
+//
+// An invalid range will be returned for disconnected ranges.
+func (r Range[C]) Union(connectedRange Range[C]) Range[C] {
+	union, _ := r.UnionE(connectedRange)
+	return union
+}
+
+// UnionE returns the range containing every value of both this range and
+// connectedRange, if such a range exists.
+//
+// For example, the union of [1..5] and (3..7) is [1..7), and the union of
+// [2..4) and [4..6) is [2..6). Unlike Span, which fills the gap between
+// disconnected ranges with values contained in neither of them, the union
+// exists if and only if the two ranges are connected, in which case it is
+// equal to their span.
+//
+// An error will be returned for disconnected ranges.
+func (r Range[C]) UnionE(connectedRange Range[C]) (Range[C], error) {
+	if err := r.checkOrder(connectedRange); err != nil {
+		return Invalid[C](), err
+	}
+	if !r.IsConnected(connectedRange) {
+		return Invalid[C](), fmt.Errorf(
+			"union is undefined for disconnected ranges %s and %s",
+			r, connectedRange)
+	}
+	return r.SpanE(connectedRange)
+}
+
+// UnionSet returns the set union of this range and other, which always exists:
+// a set holding one coalesced range when they are connected, and the two
+// ranges apart otherwise. For example, the union set of [1..5] and [4..7] is
+// {[1..7]}, while the one of [1..3] and [5..7] is {[1..3], [5..7]}.
+//
+// Unlike Span, the result contains no value outside both ranges. Empty and
+// invalid ranges contribute no values to the result.
+func (r Range[C]) UnionSet(other Range[C]) RangeSet[C] {
+	return NewRangeSet(r, other)
+}
+
+// Canonical returns the canonical form of this range in domain, the range
+// holding the same values of domain with a closed lower bound and an open
+// upper bound wherever possible. Nonempty ranges holding the same values of
+// domain have the same canonical form, so that canonical forms can be
+// compared with Equal: the canonical form of both (1..4) and [2..3] in the
+// integers is [2..4).
+//
+// An unbounded lower side becomes closed at the minimum value of domain, if
+// it has one. The upper side stays unbounded, and so does an upper bound
+// closed at the maximum value of domain, as no value follows it: AtLeast(v)
+// and [v..v] are both [v..+∞) in canonical form for the maximum value v. A
+// range holding no value above the maximum v, such as (v..+∞), has the empty
+// canonical form [v..v).
+//
+// The invalid range is its own canonical form.
+func (r Range[C]) Canonical(domain DiscreteDomain[C]) Range[C] {
+	if r.IsInvalid() {
+		return r
+	}
+	lower := r.lowerBound.canonical(domain)
+	upper := r.upperBound.canonical(domain)
+	if lower.cutType == AboveAll {
+		// only possible for (v..+∞) and (v..v], v being the maximum value
+		empty := r.lowerBound.belowValue(r.lowerBound.endpoint)
+		return Range[C]{lowerBound: empty, upperBound: empty}
+	}
+	return Range[C]{lowerBound: lower, upperBound: upper}
+}
+
+// Values returns a sequence of the values of domain contained in this range,
+// in ascending order. For example, [3..7) yields 3, 4, 5 and 6 in the
+// integers.
+//
+// The sequence is endless for ranges unbounded above in domains without a
+// maximum value, and stops at the maximum value otherwise, never wrapping
+// around. Ranges unbounded below start at the minimum value of domain, such
+// as math.MinInt for LessThan(5) in the ints, while in domains without a
+// minimum value they yield no value, like empty ranges and the invalid range.
+func (r Range[C]) Values(domain DiscreteDomain[C]) iter.Seq[C] {
+	return func(yield func(C) bool) {
+		if r.IsInvalid() {
+			return
+		}
+		lower := r.Canonical(domain).lowerBound
+		if lower.cutType != BelowValue {
+			return
+		}
+		for value, ok := lower.endpoint, true; ok && r.Contains(value); value, ok = domain.Next(value) {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesDesc returns a sequence of the values of domain contained in this
+// range, in descending order. For example, (3..7] yields 7, 6, 5 and 4 in the
+// integers, and [3..7) starts at 6, the predecessor of its open endpoint.
+//
+// It mirrors Values: the sequence stops at the minimum value of domain, never
+// wrapping around, and is endless for ranges unbounded below in domains
+// without a minimum value. Ranges unbounded above start at the maximum value
+// of domain, such as math.MaxInt for AtMost(5) in the ints, while in domains
+// without a maximum value they yield no value, like empty ranges and the
+// invalid range.
+func (r Range[C]) ValuesDesc(domain DiscreteDomain[C]) iter.Seq[C] {
+	return func(yield func(C) bool) {
+		if r.IsInvalid() {
+			return
+		}
+		var (
+			value C
+			ok    bool
+		)
+		upper := r.Canonical(domain).upperBound
+		switch upper.cutType {
+		case BelowValue:
+			value, ok = domain.Previous(upper.endpoint)
+		case AboveAll:
+			value, ok = domain.MaxValue()
+		}
+		for ; ok && r.Contains(value); value, ok = domain.Previous(value) {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Count returns the number of values of domain contained in this range. For
+// example, in the integers, [1..5] holds 5 values, [1..5) holds 4, and (1..2)
+// holds none.
+//
+// An unbounded side is counted up to the minimum or maximum value of domain,
+// so that AtLeast(v) holds a finite number of integers of a given type. The
+// count saturates at math.MaxInt64, as Distance does.
+//
+// An error will be returned if this range is invalid, or
+// ErrRangeSideUnbounded if it is unbounded on a side where domain has no
+// minimum or maximum value.
+func (r Range[C]) Count(domain DiscreteDomain[C]) (int64, error) {
+	if r.IsInvalid() {
+		return 0, fmt.Errorf("cannot count values of invalid range")
+	}
+	canonical := r.Canonical(domain)
+	if canonical.lowerBound.cutType != BelowValue {
+		return 0, ErrRangeSideUnbounded
+	}
+	first := canonical.lowerBound.endpoint
+	if canonical.upperBound.cutType == BelowValue {
+		return domain.Distance(first, canonical.upperBound.endpoint), nil
+	}
+	maxValue, ok := domain.MaxValue()
+	if !ok {
+		return 0, ErrRangeSideUnbounded
+	}
+	count := domain.Distance(first, maxValue)
+	if count < math.MaxInt64 {
+		count++
+	}
+	return count, nil
+}
+
+// ToSlice returns the values of domain contained in this range in ascending
+// order, as yielded by Values. An empty range yields an empty, non-nil
+// slice.
+//
+// An error will be returned if this range is invalid, or
+// ErrRangeSideUnbounded if it is unbounded on either side, even in a domain
+// with minimum and maximum values, to avoid huge allocations.
+func (r Range[C]) ToSlice(domain DiscreteDomain[C]) ([]C, error) {
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot list values of invalid range")
+	}
+	if !r.HasLowerBound() || !r.HasUpperBound() {
+		return nil, ErrRangeSideUnbounded
+	}
+	count, err := r.Count(domain)
+	if err != nil {
+		return nil, err
+	}
+	if count > math.MaxInt {
+		return nil, fmt.Errorf("cannot list %d values of %s", count, r)
+	}
+	values := make([]C, 0, count)
+	for value := range r.Values(domain) {
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// Split partitions this range into n adjacent ranges in canonical form, see
+// Canonical, which together hold the same values of domain as this range and
+// share none. The values are spread as evenly as possible, the leading ranges
+// holding one more value when they cannot be spread evenly: in the integers,
+// [0..100) splits into [0..25), [25..50), [50..75) and [75..100) with n = 4,
+// and [0..10) into [0..4), [4..7) and [7..10) with n = 3.
+//
+// A range holding less than n values is split into as many ranges as it holds
+// values, one each, thus an empty range yields no range. The last range ends
+// as this range does if this range reaches the maximum value of domain, such
+// as [126..127] for the int8s, rather than being unbounded above as its
+// canonical form is.
+//
+// IntegerDomain steps over the values of each range at once, while other
+// domains are walked value by value with Next.
+//
+// An error will be returned if this range is invalid, if n is not positive,
+// if this range holds math.MaxInt64 values or more, or ErrRangeSideUnbounded
+// if it is unbounded on either side.
+func (r Range[C]) Split(n int, domain DiscreteDomain[C]) ([]Range[C], error) {
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot split invalid range")
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("cannot split %s into %d ranges", r, n)
+	}
+	if !r.HasLowerBound() || !r.HasUpperBound() {
+		return nil, ErrRangeSideUnbounded
+	}
+	count, err := r.Count(domain)
+	if err != nil {
+		return nil, err
+	}
+	if count == math.MaxInt64 {
+		// the count may have saturated
+		return nil, fmt.Errorf("cannot split %s holding too many values", r)
+	}
+	if count == 0 {
+		return []Range[C]{}, nil
+	}
+
+	canonical := r.Canonical(domain)
+	last := canonical.upperBound
+	if last.cutType == AboveAll {
+		// past the maximum value of domain
+		last = r.upperBound
+	}
+	parts := min(int64(n), count)
+	size, remainder := count/parts, count%parts
+	ranges := make([]Range[C], 0, parts)
+	lower := canonical.lowerBound
+	for i := range parts {
+		steps := size
+		if i < remainder {
+			steps++
+		}
+		upper := last
+		if next, ok := advance(domain, lower.endpoint, steps); ok && i < parts-1 {
+			upper = lower.belowValue(next)
+		}
+		ranges = append(ranges, Range[C]{lowerBound: lower, upperBound: upper})
+		lower = upper
+	}
+	return ranges, nil
+}
+
 // Equal returns true if object is a range having the same endpoints and bound
 // types as this range. Note that discrete ranges such as (1..4) and [2..3] are
 // not equal to one another, despite the fact that they each contain precisely
@@ -331,12 +1022,262 @@ func (r Range[C]) SpanE(other Range[C]) (Range[C], error) {
 // have exactly the same representation, so [3..3), (3..3], (4..4] are all
 // unequal.
 func (r Range[C]) Equal(other Range[C]) bool {
-	return r.lowerBound.Compare(other.lowerBound) == 0 &&
+	return sameOrder(r.order(), other.order()) &&
+		r.lowerBound.Compare(other.lowerBound) == 0 &&
 		r.upperBound.Compare(other.upperBound) == 0
 }
 
+// Compare compares this range with other in lexicographic order: by their
+// lower bounds, then by their upper bounds, returning a negative number, zero
+// or a positive number as this range sorts before, the same as or after
+// other. It defines a total order over ranges consistent with Equal, so that
+// sorted ranges can be searched with slices.BinarySearchFunc, and invalid
+// ranges sort after every valid range. See the package function Compare.
+func (r Range[C]) Compare(other Range[C]) int {
+	return Compare(r, other)
+}
+
 func (r Range[C]) String() string {
 	lowerStr := r.lowerBound.DescribeAsLowerBound()
 	upperStr := r.upperBound.DescribeAsUpperBound()
 	return fmt.Sprintf("%s..%s", lowerStr, upperStr)
 }
+
+// RangeKey is a comparable representation of a range, for using ranges as map
+// keys or deduplicating them. The endpoint of an unbounded side is the zero
+// value of C, with the bound type Unbounded, and every field of the key of an
+// invalid range is zero but Invalid.
+//
+// The keys of two ranges are equal exactly when the ranges are Equal, as long
+// as == agrees with the ordering of C, as it does for Comparable types except
+// for NaN endpoints. The key does not hold the Comparator of a range.
+type RangeKey[C any] struct {
+	Lower     C
+	LowerType BoundType
+	Upper     C
+	UpperType BoundType
+	Invalid   bool
+}
+
+// Key returns the RangeKey of this range, for example
+//
+//	seen := make(map[granges.RangeKey[int]]bool)
+//	for _, r := range ranges {
+//		if !seen[r.Key()] {
+//			seen[r.Key()] = true
+//			unique = append(unique, r)
+//		}
+//	}
+func (r Range[C]) Key() RangeKey[C] {
+	if r.IsInvalid() {
+		return RangeKey[C]{Invalid: true}
+	}
+	k := RangeKey[C]{LowerType: Unbounded, UpperType: Unbounded}
+	if r.HasLowerBound() {
+		k.Lower, k.LowerType = r.LowerEndpoint(), r.LowerBoundType()
+	}
+	if r.HasUpperBound() {
+		k.Upper, k.UpperType = r.UpperEndpoint(), r.UpperBoundType()
+	}
+	return k
+}
+
+// RangeKind identifies which of the nine basic types of ranges a range is,
+// named after the functions constructing them.
+type RangeKind int
+
+const (
+	KindInvalid     RangeKind = iota // the invalid range
+	KindOpen                         // (a..b)
+	KindClosed                       // [a..b]
+	KindOpenClosed                   // (a..b]
+	KindClosedOpen                   // [a..b)
+	KindGreaterThan                  // (a..+∞)
+	KindAtLeast                      // [a..+∞)
+	KindLessThan                     // (-∞..b)
+	KindAtMost                       // (-∞..b]
+	KindAll                          // (-∞..+∞)
+)
+
+func (k RangeKind) String() string {
+	switch k {
+	case KindInvalid:
+		return "Invalid"
+	case KindOpen:
+		return "Open"
+	case KindClosed:
+		return "Closed"
+	case KindOpenClosed:
+		return "OpenClosed"
+	case KindClosedOpen:
+		return "ClosedOpen"
+	case KindGreaterThan:
+		return "GreaterThan"
+	case KindAtLeast:
+		return "AtLeast"
+	case KindLessThan:
+		return "LessThan"
+	case KindAtMost:
+		return "AtMost"
+	case KindAll:
+		return "All"
+	default:
+		return "unknown"
+	}
+}
+
+// Kind returns which of the nine basic types of ranges this range is, from
+// the types of its bounds: [1..5) is ClosedOpen and (-∞..5] is AtMost. Empty
+// and singleton ranges are of the kind they were built as, such as
+// ClosedOpen for [4..4) and Closed for [4..4]. KindInvalid is returned for
+// the invalid range.
+func (r Range[C]) Kind() RangeKind {
+	if r.IsInvalid() {
+		return KindInvalid
+	}
+	switch lower, upper := r.LowerBoundType(), r.UpperBoundType(); {
+	case lower == Unbounded && upper == Unbounded:
+		return KindAll
+	case lower == Unbounded && upper == OPEN:
+		return KindLessThan
+	case lower == Unbounded:
+		return KindAtMost
+	case upper == Unbounded && lower == OPEN:
+		return KindGreaterThan
+	case upper == Unbounded:
+		return KindAtLeast
+	case lower == OPEN && upper == OPEN:
+		return KindOpen
+	case lower == OPEN:
+		return KindOpenClosed
+	case upper == OPEN:
+		return KindClosedOpen
+	default:
+		return KindClosed
+	}
+}
+
+// IntersectAll returns the intersection of every range in ranges, folding
+// IntersectionE over them. All is returned for an empty slice. The result
+// may be empty, such as the intersection of [1..5) and [5..9), which is
+// [5..5).
+//
+// An invalid range with an error will be returned as soon as a range is
+// invalid or disconnected from the intersection of the ranges before it.
+func IntersectAll[C any](ranges []Range[C]) (Range[C], error) {
+	if len(ranges) == 0 {
+		return All[C](), nil
+	}
+	intersection := ranges[0]
+	for i, r := range ranges {
+		if r.IsInvalid() {
+			return Invalid[C](), fmt.Errorf("cannot intersect invalid range at index %d", i)
+		}
+		var err error
+		if intersection, err = intersection.IntersectionE(r); err != nil {
+			return Invalid[C](), fmt.Errorf("range at index %d: %w", i, err)
+		}
+	}
+	return intersection, nil
+}
+
+// SpanAll returns the minimal range enclosing every range in ranges, folding
+// SpanE over them. As span is commutative and associative, the result does
+// not depend on the order of ranges.
+//
+// An invalid range with an error will be returned if ranges is empty, as no
+// range is minimal among those enclosing no range, or if any range is
+// invalid.
+func SpanAll[C any](ranges []Range[C]) (Range[C], error) {
+	if len(ranges) == 0 {
+		return Invalid[C](), fmt.Errorf("cannot span no ranges")
+	}
+	span := ranges[0]
+	for i, r := range ranges {
+		if r.IsInvalid() {
+			return Invalid[C](), fmt.Errorf("cannot span invalid range at index %d", i)
+		}
+		var err error
+		if span, err = span.SpanE(r); err != nil {
+			return Invalid[C](), err
+		}
+	}
+	return span, nil
+}
+
+// CompareLowerBound compares the lower bounds of a and b, returning a
+// negative number if the one of a is lower, a positive number if it is
+// higher, and zero if they are the same. An unbounded lower side is lower
+// than any bounded one, and a closed lower bound is lower than the open one
+// at the same endpoint, so that [3..x sorts before (3..x. It can be used with
+// slices.SortFunc to sort ranges by their lower bound.
+//
+// Invalid ranges sort after every valid range, and are the same as each
+// other. Ranges built by different Comparators are not compared by their
+// bounds but sorted by ordering: ranges ordered naturally first, then those
+// of each Comparator in the order the Comparators were created.
+func CompareLowerBound[C any](a, b Range[C]) int {
+	if n, ok := compareInvalid(a, b); ok {
+		return n
+	}
+	if n := cmp.Compare(orderRank(a.order()), orderRank(b.order())); n != 0 {
+		return n
+	}
+	return a.lowerBound.Compare(b.lowerBound)
+}
+
+// CompareUpperBound compares the upper bounds of a and b, like
+// CompareLowerBound does for lower bounds: an unbounded upper side is higher
+// than any bounded one, and an open upper bound is lower than the closed one
+// at the same endpoint, so that x..3) sorts before x..3].
+//
+// Invalid ranges sort after every valid range, and are the same as each
+// other. Ranges built by different Comparators are sorted by ordering, as
+// CompareLowerBound does.
+func CompareUpperBound[C any](a, b Range[C]) int {
+	if n, ok := compareInvalid(a, b); ok {
+		return n
+	}
+	if n := cmp.Compare(orderRank(a.order()), orderRank(b.order())); n != 0 {
+		return n
+	}
+	return a.upperBound.Compare(b.upperBound)
+}
+
+// Compare compares a and b by their lower bounds, then by their upper bounds
+// for ranges with the same lower bound, returning a negative number, zero or
+// a positive number as a sorts before, the same as or after b. For example,
+// [1..5) sorts before [1..5], which sorts before (1..2).
+//
+// Compare returns zero exactly for ranges equal according to Range.Equal, and
+// invalid ranges sort after every valid range. Ranges built by different
+// Comparators, which are never equal, are sorted by ordering first; see
+// CompareLowerBound.
+func Compare[C any](a, b Range[C]) int {
+	if n := CompareLowerBound(a, b); n != 0 {
+		return n
+	}
+	return CompareUpperBound(a, b)
+}
+
+// SortRanges sorts ranges in place in the order defined by Compare: by lower
+// bound, then by upper bound, invalid ranges last. The sort is stable, so
+// that equal ranges keep their relative order.
+func SortRanges[C any](ranges []Range[C]) {
+	slices.SortStableFunc(ranges, Compare[C])
+}
+
+// compareInvalid compares a and b when either of them is invalid, an invalid
+// range sorting after every valid range, and returns false if both are valid.
+func compareInvalid[C any](a, b Range[C]) (int, bool) {
+	switch {
+	case a.IsInvalid() && b.IsInvalid():
+		return 0, true
+	case a.IsInvalid():
+		return 1, true
+	case b.IsInvalid():
+		return -1, true
+	default:
+		return 0, false
+	}
+}
@@ -1,6 +1,10 @@
 package granges_test
 
 import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -358,3 +362,965 @@ func TestRange_Equal(t *testing.T) {
 	assert.True(t, granges.Closed(1, 7).Equal(granges.New(1, granges.CLOSED, 7, granges.CLOSED)))
 	assert.True(t, granges.ClosedOpen(1, 7).Equal(granges.New(1, granges.CLOSED, 7, granges.OPEN)))
 }
+
+func TestRange_EnclosesAll(t *testing.T) {
+	r := granges.Closed(0, 10)
+	assert.True(t, r.EnclosesAll(nil))
+	assert.True(t, r.EnclosesAll([]granges.Range[int]{granges.Closed(0, 3), granges.Open(5, 10), r}))
+	assert.False(t, r.EnclosesAll([]granges.Range[int]{granges.Closed(0, 3), granges.Closed(5, 11)}))
+	assert.False(t, r.EnclosesAll([]granges.Range[int]{granges.AtLeast(5)}))
+	assert.True(t, granges.All[int]().EnclosesAll([]granges.Range[int]{granges.AtLeast(5), granges.LessThan(0)}))
+}
+
+func TestRange_EnclosesAny(t *testing.T) {
+	r := granges.Closed(0, 10)
+	assert.False(t, r.EnclosesAny(nil))
+	assert.True(t, r.EnclosesAny([]granges.Range[int]{granges.Closed(-1, 3), granges.Open(5, 10)}))
+	assert.False(t, r.EnclosesAny([]granges.Range[int]{granges.Closed(-1, 3), granges.AtMost(10)}))
+}
+
+func TestRange_IsConnectedToAny(t *testing.T) {
+	sorted := []granges.Range[int]{
+		granges.LessThan(0),
+		granges.Closed(2, 4),
+		granges.Open(6, 8),
+		granges.AtLeast(10),
+	}
+
+	tests := []struct {
+		R     granges.Range[int]
+		Index int
+		Want  bool
+	}{
+		{R: granges.Singleton(-5), Index: 0, Want: true},
+		{R: granges.Closed(0, 1), Index: 0, Want: true}, // touching (-∞..0)
+		{R: granges.Open(0, 2), Index: 1, Want: true},
+		{R: granges.Open(0, 1), Index: -1, Want: false},
+		{R: granges.Closed(3, 7), Index: 1, Want: true},
+		{R: granges.Open(4, 6), Index: 1, Want: true},
+		{R: granges.Closed(8, 9), Index: 2, Want: true},
+		{R: granges.OpenClosed(8, 9), Index: -1, Want: false},
+		{R: granges.Open(8, 10), Index: 3, Want: true},
+		{R: granges.Open(8, 9), Index: -1, Want: false},
+		{R: granges.All[int](), Index: 0, Want: true},
+		{R: granges.GreaterThan(4), Index: 1, Want: true},
+		{R: granges.GreaterThan(20), Index: 3, Want: true},
+	}
+
+	for _, tt := range tests {
+		i, ok := tt.R.IsConnectedToAny(sorted)
+		if i != tt.Index || ok != tt.Want {
+			t.Errorf("%v.IsConnectedToAny() = (%d, %v), want (%d, %v)", tt.R, i, ok, tt.Index, tt.Want)
+		}
+		i, ok = tt.R.IsConnectedToAnySorted(sorted)
+		if i != tt.Index || ok != tt.Want {
+			t.Errorf("%v.IsConnectedToAnySorted() = (%d, %v), want (%d, %v)", tt.R, i, ok, tt.Index, tt.Want)
+		}
+	}
+
+	_, ok := granges.Closed(1, 2).IsConnectedToAny(nil)
+	assert.False(t, ok)
+	_, ok = granges.Closed(1, 2).IsConnectedToAnySorted(nil)
+	assert.False(t, ok)
+}
+
+func TestRange_Complement(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want []granges.Range[int]
+	}{
+		{R: granges.Closed(3, 8), Want: []granges.Range[int]{granges.LessThan(3), granges.GreaterThan(8)}},
+		{R: granges.Open(3, 8), Want: []granges.Range[int]{granges.AtMost(3), granges.AtLeast(8)}},
+		{R: granges.ClosedOpen(3, 8), Want: []granges.Range[int]{granges.LessThan(3), granges.AtLeast(8)}},
+		{R: granges.OpenClosed(3, 8), Want: []granges.Range[int]{granges.AtMost(3), granges.GreaterThan(8)}},
+		{R: granges.LessThan(5), Want: []granges.Range[int]{granges.AtLeast(5)}},
+		{R: granges.AtMost(5), Want: []granges.Range[int]{granges.GreaterThan(5)}},
+		{R: granges.GreaterThan(5), Want: []granges.Range[int]{granges.AtMost(5)}},
+		{R: granges.AtLeast(5), Want: []granges.Range[int]{granges.LessThan(5)}},
+		{R: granges.All[int](), Want: []granges.Range[int]{}},
+		{R: granges.ClosedOpen(4, 4), Want: []granges.Range[int]{granges.LessThan(4), granges.AtLeast(4)}},
+		{R: granges.Singleton(4), Want: []granges.Range[int]{granges.LessThan(4), granges.GreaterThan(4)}},
+	}
+
+	for _, tt := range tests {
+		complement := tt.R.Complement()
+		if assert.Len(t, complement, len(tt.Want), "%v.Complement()", tt.R) {
+			for i := range tt.Want {
+				assert.True(t, tt.Want[i].Equal(complement[i]), "%v.Complement()[%d] = %v, want %v", tt.R, i, complement[i], tt.Want[i])
+			}
+		}
+
+		for v := -2; v <= 12; v++ {
+			inComplement := false
+			for _, c := range complement {
+				inComplement = inComplement || c.Contains(v)
+			}
+			assert.NotEqual(t, tt.R.Contains(v), inComplement, "%v contains %d", tt.R, v)
+		}
+	}
+
+	assert.Nil(t, granges.Invalid[int]().Complement())
+}
+
+func TestRange_Union(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want granges.Range[int]
+	}{
+		{A: granges.Closed(1, 5), B: granges.Open(3, 7), Want: granges.ClosedOpen(1, 7)},
+		{A: granges.ClosedOpen(2, 4), B: granges.ClosedOpen(4, 6), Want: granges.ClosedOpen(2, 6)},
+		{A: granges.ClosedOpen(4, 6), B: granges.ClosedOpen(2, 4), Want: granges.ClosedOpen(2, 6)},
+		{A: granges.OpenClosed(2, 4), B: granges.Open(4, 6), Want: granges.Open(2, 6)},
+		{A: granges.Closed(2, 8), B: granges.Closed(4, 6), Want: granges.Closed(2, 8)},
+		{A: granges.Closed(2, 4), B: granges.ClosedOpen(4, 4), Want: granges.Closed(2, 4)},
+		{A: granges.ClosedOpen(4, 4), B: granges.AtLeast(4), Want: granges.AtLeast(4)},
+		{A: granges.ClosedOpen(4, 4), B: granges.ClosedOpen(4, 4), Want: granges.ClosedOpen(4, 4)},
+		{A: granges.LessThan(3), B: granges.AtLeast(3), Want: granges.All[int]()},
+	}
+
+	for _, tt := range tests {
+		union, err := tt.A.UnionE(tt.B)
+		assert.NoError(t, err)
+		if !union.Equal(tt.Want) {
+			t.Errorf("UnionE(%v, %v) = %v, want %v", tt.A, tt.B, union, tt.Want)
+		}
+		assert.True(t, tt.Want.Equal(tt.A.Union(tt.B)))
+	}
+
+	union, err := granges.Closed(1, 3).UnionE(granges.Closed(5, 7))
+	assert.EqualError(t, err, "union is undefined for disconnected ranges [1..3] and [5..7]")
+	assert.True(t, union.IsInvalid())
+
+	union, err = granges.ClosedOpen(4, 4).UnionE(granges.Closed(5, 7))
+	assert.ErrorContains(t, err, "disconnected")
+	assert.True(t, union.IsInvalid())
+
+	// (2..4) and (4..6) are not connected, as no range is enclosed by both
+	assert.True(t, granges.Open(2, 4).Union(granges.Open(4, 6)).IsInvalid())
+}
+
+func TestRange_Intersects(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want bool
+	}{
+		{A: granges.ClosedOpen(2, 4), B: granges.ClosedOpen(3, 5), Want: true},
+		{A: granges.ClosedOpen(2, 4), B: granges.ClosedOpen(4, 6), Want: false},
+		{A: granges.Closed(2, 4), B: granges.ClosedOpen(4, 6), Want: true},
+		{A: granges.Closed(2, 4), B: granges.OpenClosed(4, 6), Want: false},
+		{A: granges.Closed(2, 4), B: granges.Closed(5, 6), Want: false},
+		{A: granges.Open(2, 8), B: granges.Singleton(5), Want: true},
+		{A: granges.ClosedOpen(4, 4), B: granges.Closed(2, 6), Want: false},
+		{A: granges.Closed(2, 6), B: granges.OpenClosed(4, 4), Want: false},
+		{A: granges.LessThan(3), B: granges.AtLeast(3), Want: false},
+		{A: granges.AtMost(3), B: granges.AtLeast(3), Want: true},
+		{A: granges.All[int](), B: granges.GreaterThan(100), Want: true},
+		{A: granges.All[int](), B: granges.All[int](), Want: true},
+		{A: granges.All[int](), B: granges.Invalid[int](), Want: false},
+	}
+
+	for _, tt := range tests {
+		if get := tt.A.Intersects(tt.B); get != tt.Want {
+			t.Errorf("%v.Intersects(%v) = %v, want %v", tt.A, tt.B, get, tt.Want)
+		}
+		if get := tt.B.Intersects(tt.A); get != tt.Want {
+			t.Errorf("%v.Intersects(%v) = %v, want %v", tt.B, tt.A, get, tt.Want)
+		}
+	}
+}
+
+// countingSeq yields values and records how many of them were pulled.
+func countingSeq(values []int, pulled *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, v := range values {
+			*pulled++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func TestRange_ContainsAllSeq(t *testing.T) {
+	r := granges.Closed(3, 5)
+
+	pulled := 0
+	assert.True(t, r.ContainsAllSeq(countingSeq([]int{3, 4, 5}, &pulled)))
+	assert.EqualValues(t, 3, pulled)
+
+	pulled = 0
+	assert.False(t, r.ContainsAllSeq(countingSeq([]int{3, 6, 4, 5}, &pulled)))
+	assert.EqualValues(t, 2, pulled)
+
+	assert.True(t, r.ContainsAllSeq(slices.Values([]int{})))
+	assert.True(t, granges.OpenClosed(3, 3).ContainsAllSeq(slices.Values([]int(nil))))
+}
+
+func TestRange_ContainsAnySeq(t *testing.T) {
+	r := granges.Closed(3, 5)
+
+	pulled := 0
+	assert.True(t, r.ContainsAnySeq(countingSeq([]int{1, 4, 9, 10}, &pulled)))
+	assert.EqualValues(t, 2, pulled)
+
+	pulled = 0
+	assert.False(t, r.ContainsAnySeq(countingSeq([]int{1, 2, 6}, &pulled)))
+	assert.EqualValues(t, 3, pulled)
+
+	assert.False(t, r.ContainsAnySeq(slices.Values([]int{})))
+	assert.False(t, granges.All[int]().ContainsAnySeq(slices.Values([]int(nil))))
+}
+
+func TestRange_IsBounded(t *testing.T) {
+	tests := []struct {
+		R                       granges.Range[int]
+		All, Bounded, Unbounded bool
+	}{
+		{R: granges.Closed(1, 5), Bounded: true},
+		{R: granges.Open(1, 5), Bounded: true},
+		{R: granges.ClosedOpen(4, 4), Bounded: true},
+		{R: granges.Singleton(4), Bounded: true},
+		{R: granges.AtLeast(1), Unbounded: true},
+		{R: granges.GreaterThan(1), Unbounded: true},
+		{R: granges.LessThan(1), Unbounded: true},
+		{R: granges.AtMost(1), Unbounded: true},
+		{R: granges.All[int](), All: true, Unbounded: true},
+		{R: granges.Invalid[int]()},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.All, tt.R.IsAll(), "%v.IsAll()", tt.R)
+		assert.Equal(t, tt.Bounded, tt.R.IsBounded(), "%v.IsBounded()", tt.R)
+		assert.Equal(t, tt.Unbounded, tt.R.IsUnbounded(), "%v.IsUnbounded()", tt.R)
+	}
+}
+
+func TestRange_IsSingleton(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want bool
+	}{
+		{R: granges.Singleton(4), Want: true},
+		{R: granges.Closed(4, 4), Want: true},
+		{R: granges.ClosedOpen(4, 4), Want: false},
+		{R: granges.OpenClosed(4, 4), Want: false},
+		{R: granges.Closed(4, 5), Want: false},
+		{R: granges.ClosedOpen(4, 5), Want: false},
+		{R: granges.AtLeast(4), Want: false},
+		{R: granges.AtMost(4), Want: false},
+		{R: granges.All[int](), Want: false},
+		{R: granges.Invalid[int](), Want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, tt.R.IsSingleton(), "%v.IsSingleton()", tt.R)
+	}
+
+	assert.True(t, byTime.Singleton(day(1)).IsSingleton())
+}
+
+func TestRange_SingletonValue(t *testing.T) {
+	v, ok := granges.Singleton(4).SingletonValue()
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	f, ok := granges.Closed(-1.5, -1.5).SingletonValue()
+	assert.True(t, ok)
+	assert.Equal(t, -1.5, f)
+
+	_, ok = granges.Closed(4, 5).SingletonValue()
+	assert.False(t, ok)
+	_, ok = granges.ClosedOpen(4, 4).SingletonValue()
+	assert.False(t, ok)
+	_, ok = granges.Invalid[int]().SingletonValue()
+	assert.False(t, ok)
+}
+
+func TestRange_AsPredicate(t *testing.T) {
+	contains := granges.ClosedOpen(3, 5).AsPredicate()
+	assert.False(t, contains(2))
+	assert.True(t, contains(3))
+	assert.True(t, contains(4))
+	assert.False(t, contains(5))
+
+	assert.Equal(t, 2, slices.IndexFunc([]int{1, 7, 4, 3}, contains))
+	assert.False(t, granges.ClosedOpen(3, 3).AsPredicate()(3))
+	assert.True(t, granges.All[int]().AsPredicate()(math.MinInt))
+
+	invalid := granges.Invalid[int]().AsPredicate()
+	assert.NotNil(t, invalid)
+	assert.False(t, invalid(0))
+}
+
+func TestRange_NotPredicate(t *testing.T) {
+	values := slices.DeleteFunc([]int{1, 3, 4, 5, 7}, granges.ClosedOpen(3, 5).NotPredicate())
+	assert.Equal(t, []int{3, 4}, values)
+
+	values = slices.DeleteFunc([]int{1, 3}, granges.ClosedOpen(3, 3).NotPredicate())
+	assert.Empty(t, values)
+	values = slices.DeleteFunc([]int{1, 3}, granges.All[int]().NotPredicate())
+	assert.Equal(t, []int{1, 3}, values)
+	assert.True(t, granges.Invalid[int]().NotPredicate()(0))
+}
+
+func TestRange_ClampTo(t *testing.T) {
+	tests := []struct {
+		R, Window granges.Range[int]
+		Want      granges.Range[int]
+	}{
+		{R: granges.Closed(-10, 50), Window: granges.Closed(0, 30), Want: granges.Closed(0, 30)},
+		{R: granges.Closed(10, 20), Window: granges.Closed(0, 30), Want: granges.Closed(10, 20)},
+		{R: granges.Closed(10, 50), Window: granges.ClosedOpen(0, 30), Want: granges.ClosedOpen(10, 30)},
+		{R: granges.Closed(40, 50), Window: granges.Closed(0, 30), Want: granges.ClosedOpen(30, 30)},
+		{R: granges.Closed(40, 50), Window: granges.ClosedOpen(0, 30), Want: granges.ClosedOpen(30, 30)},
+		{R: granges.Closed(-20, -10), Window: granges.Closed(0, 30), Want: granges.ClosedOpen(0, 0)},
+		{R: granges.Closed(-20, -10), Window: granges.OpenClosed(0, 30), Want: granges.OpenClosed(0, 0)},
+		{R: granges.Closed(40, 50), Window: granges.OpenClosed(0, 30), Want: granges.ClosedOpen(30, 30)},
+		{R: granges.Closed(40, 50), Window: granges.Open(0, 30), Want: granges.ClosedOpen(30, 30)},
+		{R: granges.LessThan(0), Window: granges.Closed(0, 30), Want: granges.ClosedOpen(0, 0)},
+		{R: granges.OpenClosed(30, 40), Window: granges.Closed(0, 30), Want: granges.OpenClosed(30, 30)},
+		{R: granges.All[int](), Window: granges.Closed(0, 30), Want: granges.Closed(0, 30)},
+		{R: granges.Closed(-10, 50), Window: granges.AtLeast(0), Want: granges.Closed(0, 50)},
+		{R: granges.Closed(-10, -5), Window: granges.AtLeast(0), Want: granges.ClosedOpen(0, 0)},
+		{R: granges.Closed(-10, 50), Window: granges.All[int](), Want: granges.Closed(-10, 50)},
+		{R: granges.ClosedOpen(5, 5), Window: granges.Closed(0, 30), Want: granges.ClosedOpen(5, 5)},
+		{R: granges.ClosedOpen(50, 50), Window: granges.Closed(0, 30), Want: granges.ClosedOpen(30, 30)},
+		{R: granges.Closed(10, 20), Window: granges.ClosedOpen(5, 5), Want: granges.ClosedOpen(5, 5)},
+	}
+
+	for _, tt := range tests {
+		get := tt.R.ClampTo(tt.Window)
+		if !get.Equal(tt.Want) || get.IsInvalid() {
+			t.Errorf("%v.ClampTo(%v) = %v, want %v", tt.R, tt.Window, get, tt.Want)
+		}
+		assert.True(t, tt.Window.Encloses(get))
+	}
+
+	assert.True(t, granges.Invalid[int]().ClampTo(granges.All[int]()).IsInvalid())
+	assert.True(t, granges.All[int]().ClampTo(granges.Invalid[int]()).IsInvalid())
+}
+
+func TestRange_UnionSet(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want string
+	}{
+		{A: granges.Closed(1, 3), B: granges.Closed(5, 7), Want: "{[1..3], [5..7]}"},
+		{A: granges.Closed(5, 7), B: granges.Closed(1, 3), Want: "{[1..3], [5..7]}"},
+		{A: granges.Closed(1, 5), B: granges.Closed(4, 7), Want: "{[1..7]}"},
+		{A: granges.ClosedOpen(1, 4), B: granges.ClosedOpen(4, 7), Want: "{[1..7)}"},
+		{A: granges.Open(1, 4), B: granges.Open(4, 7), Want: "{(1..4), (4..7)}"},
+		{A: granges.Closed(1, 10), B: granges.Closed(4, 7), Want: "{[1..10]}"},
+		{A: granges.LessThan(0), B: granges.GreaterThan(0), Want: "{(-∞..0), (0..+∞)}"},
+		{A: granges.ClosedOpen(4, 4), B: granges.Closed(5, 7), Want: "{[5..7]}"},
+		{A: granges.ClosedOpen(4, 4), B: granges.OpenClosed(4, 4), Want: "{}"},
+	}
+
+	for _, tt := range tests {
+		assert.EqualValues(t, tt.Want, tt.A.UnionSet(tt.B).String(), "%v.UnionSet(%v)", tt.A, tt.B)
+	}
+}
+
+func TestRange_Difference(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want []granges.Range[int]
+	}{
+		{A: granges.Closed(1, 10), B: granges.Closed(3, 5), Want: []granges.Range[int]{granges.ClosedOpen(1, 3), granges.OpenClosed(5, 10)}},
+		{A: granges.Closed(1, 10), B: granges.Open(3, 5), Want: []granges.Range[int]{granges.Closed(1, 3), granges.Closed(5, 10)}},
+		{A: granges.Open(1, 10), B: granges.ClosedOpen(3, 5), Want: []granges.Range[int]{granges.Open(1, 3), granges.ClosedOpen(5, 10)}},
+		{A: granges.Closed(1, 10), B: granges.AtLeast(5), Want: []granges.Range[int]{granges.ClosedOpen(1, 5)}},
+		{A: granges.Closed(1, 10), B: granges.LessThan(5), Want: []granges.Range[int]{granges.Closed(5, 10)}},
+		{A: granges.Closed(1, 10), B: granges.Closed(1, 5), Want: []granges.Range[int]{granges.OpenClosed(5, 10)}},
+		{A: granges.Closed(1, 10), B: granges.Open(1, 10), Want: []granges.Range[int]{granges.Singleton(1), granges.Singleton(10)}},
+		{A: granges.Closed(3, 5), B: granges.Closed(1, 10), Want: []granges.Range[int]{}},
+		{A: granges.Closed(3, 5), B: granges.Closed(3, 5), Want: []granges.Range[int]{}},
+		{A: granges.Closed(3, 5), B: granges.All[int](), Want: []granges.Range[int]{}},
+		{A: granges.All[int](), B: granges.Closed(3, 5), Want: []granges.Range[int]{granges.LessThan(3), granges.GreaterThan(5)}},
+		{A: granges.Closed(1, 3), B: granges.Closed(5, 7), Want: []granges.Range[int]{granges.Closed(1, 3)}},
+		{A: granges.ClosedOpen(1, 3), B: granges.Closed(3, 7), Want: []granges.Range[int]{granges.ClosedOpen(1, 3)}},
+		{A: granges.Closed(1, 10), B: granges.ClosedOpen(4, 4), Want: []granges.Range[int]{granges.Closed(1, 10)}},
+		{A: granges.ClosedOpen(4, 4), B: granges.Closed(1, 10), Want: []granges.Range[int]{}},
+	}
+
+	for _, tt := range tests {
+		difference := tt.A.Difference(tt.B)
+		if assert.Len(t, difference, len(tt.Want), "%v.Difference(%v)", tt.A, tt.B) {
+			for i := range tt.Want {
+				assert.True(t, tt.Want[i].Equal(difference[i]), "%v.Difference(%v)[%d] = %v, want %v", tt.A, tt.B, i, difference[i], tt.Want[i])
+			}
+		}
+
+		for v := -2; v <= 12; v++ {
+			inDifference := false
+			for _, d := range difference {
+				inDifference = inDifference || d.Contains(v)
+			}
+			assert.Equal(t, tt.A.Contains(v) && !tt.B.Contains(v), inDifference, "%v.Difference(%v) contains %d", tt.A, tt.B, v)
+		}
+	}
+
+	assert.Nil(t, granges.Invalid[int]().Difference(granges.Closed(1, 2)))
+	assert.Nil(t, granges.Closed(1, 2).Difference(granges.Invalid[int]()))
+}
+
+func TestRange_SymmetricDifference(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want []granges.Range[int]
+	}{
+		{A: granges.Closed(1, 5), B: granges.Closed(3, 7), Want: []granges.Range[int]{granges.ClosedOpen(1, 3), granges.OpenClosed(5, 7)}},
+		{A: granges.Closed(3, 7), B: granges.Closed(1, 5), Want: []granges.Range[int]{granges.ClosedOpen(1, 3), granges.OpenClosed(5, 7)}},
+		{A: granges.Closed(1, 10), B: granges.Open(3, 5), Want: []granges.Range[int]{granges.Closed(1, 3), granges.Closed(5, 10)}},
+		{A: granges.Closed(5, 7), B: granges.Closed(1, 3), Want: []granges.Range[int]{granges.Closed(1, 3), granges.Closed(5, 7)}},
+		{A: granges.ClosedOpen(1, 3), B: granges.Closed(3, 5), Want: []granges.Range[int]{granges.ClosedOpen(1, 3), granges.Closed(3, 5)}},
+		{A: granges.Closed(1, 5), B: granges.Closed(1, 5), Want: []granges.Range[int]{}},
+		{A: granges.Closed(1, 5), B: granges.Open(1, 5), Want: []granges.Range[int]{granges.Singleton(1), granges.Singleton(5)}},
+		{A: granges.AtMost(5), B: granges.AtLeast(3), Want: []granges.Range[int]{granges.LessThan(3), granges.GreaterThan(5)}},
+		{A: granges.ClosedOpen(4, 4), B: granges.Closed(1, 3), Want: []granges.Range[int]{granges.Closed(1, 3)}},
+		{A: granges.ClosedOpen(4, 4), B: granges.OpenClosed(4, 4), Want: []granges.Range[int]{}},
+	}
+
+	for _, tt := range tests {
+		difference := tt.A.SymmetricDifference(tt.B)
+		if assert.Len(t, difference, len(tt.Want), "%v.SymmetricDifference(%v)", tt.A, tt.B) {
+			for i := range tt.Want {
+				assert.True(t, tt.Want[i].Equal(difference[i]), "%v.SymmetricDifference(%v)[%d] = %v, want %v", tt.A, tt.B, i, difference[i], tt.Want[i])
+			}
+		}
+
+		for v := -2; v <= 12; v++ {
+			inDifference := false
+			for _, d := range difference {
+				inDifference = inDifference || d.Contains(v)
+			}
+			assert.Equal(t, tt.A.Contains(v) != tt.B.Contains(v), inDifference, "%v.SymmetricDifference(%v) contains %d", tt.A, tt.B, v)
+		}
+	}
+
+	assert.Nil(t, granges.Invalid[int]().SymmetricDifference(granges.Closed(1, 2)))
+}
+
+func TestRange_ContainsRange(t *testing.T) {
+	tests := []struct {
+		A, B granges.Range[int]
+		Want bool
+	}{
+		{A: granges.Closed(3, 6), B: granges.Closed(4, 5), Want: true},
+		{A: granges.Open(3, 6), B: granges.Open(3, 6), Want: true},
+		{A: granges.Closed(3, 6), B: granges.ClosedOpen(4, 4), Want: true},
+		{A: granges.OpenClosed(3, 6), B: granges.Closed(3, 6), Want: false},
+		{A: granges.Closed(4, 5), B: granges.Open(3, 6), Want: true},
+		{A: granges.Closed(3, 6), B: granges.OpenClosed(1, 1), Want: true},
+		{A: granges.Closed(3, 6), B: granges.Open(5, 6), Want: true},
+		{A: granges.Closed(3, 6), B: granges.Open(10, 12), Want: false},
+		{A: granges.Closed(3, 6), B: granges.Open(10, 11), Want: true},
+		{A: granges.Open(3, 6), B: granges.Closed(4, 5), Want: true},
+		{A: granges.Open(3, 6), B: granges.Closed(3, 5), Want: false},
+		{A: granges.GreaterThan(3), B: granges.AtLeast(4), Want: true},
+		{A: granges.AtLeast(4), B: granges.GreaterThan(3), Want: true},
+		{A: granges.AtLeast(4), B: granges.All[int](), Want: false},
+		{A: granges.All[int](), B: granges.Closed(3, 6), Want: true},
+		{A: granges.ClosedOpen(4, 4), B: granges.Singleton(4), Want: false},
+		{A: granges.ClosedOpen(4, 4), B: granges.OpenClosed(7, 7), Want: true},
+		{A: granges.Closed(3, 6), B: granges.LessThan(math.MinInt), Want: true},
+		{A: granges.Closed(3, 6), B: granges.GreaterThan(math.MaxInt), Want: true},
+		{A: granges.AtMost(math.MaxInt), B: granges.All[int](), Want: true},
+		{A: granges.Closed(3, 6), B: granges.Invalid[int](), Want: false},
+		{A: granges.Invalid[int](), B: granges.ClosedOpen(4, 4), Want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, tt.A.ContainsRange(tt.B), "%v.ContainsRange(%v)", tt.A, tt.B)
+		if tt.A.Encloses(tt.B) && !tt.A.IsInvalid() && !tt.B.IsInvalid() {
+			assert.True(t, tt.A.ContainsRange(tt.B), "%v encloses %v", tt.A, tt.B)
+		}
+	}
+
+	for v := 2; v <= 7; v++ {
+		assert.Equal(t, granges.Open(3, 6).Contains(v), granges.Open(3, 6).ContainsRange(granges.Singleton(v)))
+	}
+
+	assert.False(t, granges.Closed(4.0, 5.0).ContainsRange(granges.Open(3.0, 6.0)))
+	assert.True(t, granges.Closed(3.0, 6.0).ContainsRange(granges.OpenClosed(1.0, 1.0)))
+	assert.True(t, granges.Closed[uint8](0, 255).ContainsRange(granges.GreaterThan[uint8](0)))
+	assert.True(t, granges.Closed[uint8](1, 3).ContainsRange(granges.LessThan[uint8](0)))
+	assert.True(t, granges.Closed("b", "c").ContainsRange(granges.ClosedOpen("a", "a")))
+	assert.False(t, granges.Closed("b", "c").ContainsRange(granges.Open("a", "d")))
+}
+
+func TestRange_SplitAt(t *testing.T) {
+	tests := []struct {
+		R            granges.Range[int]
+		Point        int
+		BoundType    granges.BoundType
+		Lower, Upper granges.Range[int]
+	}{
+		{R: granges.Closed(0, 10), Point: 4, BoundType: granges.OPEN, Lower: granges.ClosedOpen(0, 4), Upper: granges.Closed(4, 10)},
+		{R: granges.Closed(0, 10), Point: 4, BoundType: granges.CLOSED, Lower: granges.Closed(0, 4), Upper: granges.OpenClosed(4, 10)},
+		{R: granges.Open(0, 10), Point: 4, BoundType: granges.OPEN, Lower: granges.Open(0, 4), Upper: granges.ClosedOpen(4, 10)},
+		{R: granges.Closed(0, 10), Point: 0, BoundType: granges.OPEN, Lower: granges.ClosedOpen(0, 0), Upper: granges.Closed(0, 10)},
+		{R: granges.Closed(0, 10), Point: 0, BoundType: granges.CLOSED, Lower: granges.Singleton(0), Upper: granges.OpenClosed(0, 10)},
+		{R: granges.Closed(0, 10), Point: 10, BoundType: granges.CLOSED, Lower: granges.Closed(0, 10), Upper: granges.OpenClosed(10, 10)},
+		{R: granges.Closed(0, 10), Point: 10, BoundType: granges.OPEN, Lower: granges.ClosedOpen(0, 10), Upper: granges.Singleton(10)},
+		{R: granges.Singleton(5), Point: 5, BoundType: granges.OPEN, Lower: granges.ClosedOpen(5, 5), Upper: granges.Singleton(5)},
+		{R: granges.All[int](), Point: 0, BoundType: granges.OPEN, Lower: granges.LessThan(0), Upper: granges.AtLeast(0)},
+		{R: granges.AtMost(5), Point: 0, BoundType: granges.CLOSED, Lower: granges.AtMost(0), Upper: granges.OpenClosed(0, 5)},
+	}
+
+	for _, tt := range tests {
+		lower, upper, err := tt.R.SplitAt(tt.Point, tt.BoundType)
+		assert.NoError(t, err)
+		assert.True(t, tt.Lower.Equal(lower), "%v.SplitAt(%d) lower = %v, want %v", tt.R, tt.Point, lower, tt.Lower)
+		assert.True(t, tt.Upper.Equal(upper), "%v.SplitAt(%d) upper = %v, want %v", tt.R, tt.Point, upper, tt.Upper)
+		assert.True(t, tt.R.Equal(lower.Span(upper)))
+		assert.False(t, lower.Intersects(upper))
+	}
+
+	for _, r := range []granges.Range[int]{granges.Closed(5, 10), granges.OpenClosed(4, 10), granges.ClosedOpen(0, 4), granges.ClosedOpen(4, 4), granges.Invalid[int]()} {
+		lower, upper, err := r.SplitAt(4, granges.OPEN)
+		assert.Error(t, err, "%v.SplitAt(4)", r)
+		assert.True(t, lower.IsInvalid())
+		assert.True(t, upper.IsInvalid())
+	}
+
+	_, _, err := granges.Closed(0, 10).SplitAt(4, granges.Unbounded)
+	assert.ErrorIs(t, err, granges.ErrWrongBoundType)
+}
+
+func TestRange_Canonical(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int8]
+		Want granges.Range[int8]
+	}{
+		{R: granges.Open[int8](1, 4), Want: granges.ClosedOpen[int8](2, 4)},
+		{R: granges.Closed[int8](2, 3), Want: granges.ClosedOpen[int8](2, 4)},
+		{R: granges.ClosedOpen[int8](2, 4), Want: granges.ClosedOpen[int8](2, 4)},
+		{R: granges.OpenClosed[int8](1, 3), Want: granges.ClosedOpen[int8](2, 4)},
+		{R: granges.Singleton[int8](5), Want: granges.ClosedOpen[int8](5, 6)},
+		{R: granges.Open[int8](3, 4), Want: granges.ClosedOpen[int8](4, 4)},
+		{R: granges.OpenClosed[int8](3, 3), Want: granges.ClosedOpen[int8](4, 4)},
+		{R: granges.GreaterThan[int8](3), Want: granges.AtLeast[int8](4)},
+		{R: granges.AtMost[int8](3), Want: granges.ClosedOpen[int8](math.MinInt8, 4)},
+		{R: granges.LessThan[int8](3), Want: granges.ClosedOpen[int8](math.MinInt8, 3)},
+		{R: granges.All[int8](), Want: granges.AtLeast[int8](math.MinInt8)},
+		{R: granges.AtLeast[int8](math.MaxInt8), Want: granges.AtLeast[int8](math.MaxInt8)},
+		{R: granges.Singleton[int8](math.MaxInt8), Want: granges.AtLeast[int8](math.MaxInt8)},
+		{R: granges.GreaterThan[int8](math.MaxInt8), Want: granges.ClosedOpen[int8](math.MaxInt8, math.MaxInt8)},
+		{R: granges.OpenClosed[int8](math.MaxInt8, math.MaxInt8), Want: granges.ClosedOpen[int8](math.MaxInt8, math.MaxInt8)},
+		{R: granges.LessThan[int8](math.MinInt8), Want: granges.ClosedOpen[int8](math.MinInt8, math.MinInt8)},
+	}
+
+	for _, tt := range tests {
+		canonical := tt.R.Canonical(granges.IntegerDomain[int8]())
+		assert.True(t, tt.Want.Equal(canonical), "%v.Canonical() = %v, want %v", tt.R, canonical, tt.Want)
+		assert.True(t, canonical.Equal(canonical.Canonical(granges.IntegerDomain[int8]())), "Canonical is not idempotent for %v", tt.R)
+		for v := math.MinInt8; v <= math.MaxInt8; v++ {
+			assert.Equal(t, tt.R.Contains(int8(v)), canonical.Contains(int8(v)), "%v contains %d", tt.R, v)
+		}
+	}
+
+	assert.True(t, granges.Invalid[int8]().Canonical(granges.IntegerDomain[int8]()).IsInvalid())
+}
+
+func TestRange_Values(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	tests := []struct {
+		R    granges.Range[int]
+		Want []int
+	}{
+		{R: granges.ClosedOpen(3, 7), Want: []int{3, 4, 5, 6}},
+		{R: granges.Closed(3, 7), Want: []int{3, 4, 5, 6, 7}},
+		{R: granges.Open(3, 7), Want: []int{4, 5, 6}},
+		{R: granges.Closed(-2, 1), Want: []int{-2, -1, 0, 1}},
+		{R: granges.Singleton(3), Want: []int{3}},
+		{R: granges.ClosedOpen(3, 3), Want: nil},
+		{R: granges.Open(3, 4), Want: nil},
+		{R: granges.AtLeast(math.MaxInt - 2), Want: []int{math.MaxInt - 2, math.MaxInt - 1, math.MaxInt}},
+		{R: granges.Closed(math.MaxInt-2, math.MaxInt), Want: []int{math.MaxInt - 2, math.MaxInt - 1, math.MaxInt}},
+		{R: granges.OpenClosed(math.MaxInt-2, math.MaxInt), Want: []int{math.MaxInt - 1, math.MaxInt}},
+		{R: granges.GreaterThan(math.MaxInt), Want: nil},
+		{R: granges.AtMost(math.MinInt + 1), Want: []int{math.MinInt, math.MinInt + 1}},
+		{R: granges.Invalid[int](), Want: nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, slices.Collect(tt.R.Values(ints)), "%v.Values()", tt.R)
+	}
+
+	var values []int
+	for v := range granges.AtLeast(10).Values(ints) {
+		if v == 13 {
+			break
+		}
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{10, 11, 12}, values)
+
+	assert.Len(t, slices.Collect(granges.All[uint8]().Values(granges.IntegerDomain[uint8]())), 256)
+	assert.Equal(t, []int8{125, 126, 127}, slices.Collect(granges.Closed[int8](125, 127).Values(granges.IntegerDomain[int8]())))
+
+	// ranges unbounded below start at the minimum value of the domain
+	values = nil
+	for v := range granges.LessThan(5).Values(ints) {
+		if len(values) == 2 {
+			break
+		}
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{math.MinInt, math.MinInt + 1}, values)
+	assert.Equal(t, []int{0, 1, 2}, slices.Collect(granges.LessThan(3).Values(naturals{})))
+}
+
+func TestRange_ValuesDesc(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	tests := []struct {
+		R    granges.Range[int]
+		Want []int
+	}{
+		{R: granges.ClosedOpen(3, 7), Want: []int{6, 5, 4, 3}},
+		{R: granges.Closed(3, 7), Want: []int{7, 6, 5, 4, 3}},
+		{R: granges.Open(3, 7), Want: []int{6, 5, 4}},
+		{R: granges.Closed(-2, 1), Want: []int{1, 0, -1, -2}},
+		{R: granges.Singleton(3), Want: []int{3}},
+		{R: granges.ClosedOpen(3, 3), Want: nil},
+		{R: granges.Open(3, 4), Want: nil},
+		{R: granges.AtMost(math.MinInt + 2), Want: []int{math.MinInt + 2, math.MinInt + 1, math.MinInt}},
+		{R: granges.Closed(math.MinInt, math.MinInt+2), Want: []int{math.MinInt + 2, math.MinInt + 1, math.MinInt}},
+		{R: granges.ClosedOpen(math.MinInt, math.MinInt+2), Want: []int{math.MinInt + 1, math.MinInt}},
+		{R: granges.LessThan(math.MinInt), Want: nil},
+		{R: granges.AtLeast(math.MaxInt - 1), Want: []int{math.MaxInt, math.MaxInt - 1}},
+		{R: granges.Invalid[int](), Want: nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, slices.Collect(tt.R.ValuesDesc(ints)), "%v.ValuesDesc()", tt.R)
+	}
+
+	var values []int
+	for v := range granges.AtMost(10).ValuesDesc(ints) {
+		if v == 7 {
+			break
+		}
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{10, 9, 8}, values)
+
+	assert.Len(t, slices.Collect(granges.All[uint8]().ValuesDesc(granges.IntegerDomain[uint8]())), 256)
+	assert.Equal(t, []uint8{2, 1, 0}, slices.Collect(granges.Closed[uint8](0, 2).ValuesDesc(granges.IntegerDomain[uint8]())))
+
+	// ranges unbounded above start at the maximum value of the domain
+	values = nil
+	for v := range granges.GreaterThan(5).ValuesDesc(ints) {
+		if len(values) == 2 {
+			break
+		}
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{math.MaxInt, math.MaxInt - 1}, values)
+	assert.Empty(t, slices.Collect(granges.AtLeast(3).ValuesDesc(naturals{})))
+	assert.Equal(t, []int{2, 1, 0}, slices.Collect(granges.LessThan(3).ValuesDesc(naturals{})))
+}
+
+// naturals is the discrete domain of non-negative ints, without a maximum.
+type naturals struct{}
+
+func (naturals) Next(value int) (int, bool) { return value + 1, true }
+
+func (naturals) Previous(value int) (int, bool) { return value - 1, value > 0 }
+
+func (naturals) MinValue() (int, bool) { return 0, true }
+
+func (naturals) MaxValue() (int, bool) { return 0, false }
+
+func (naturals) Distance(a, b int) int64 { return int64(b - a) }
+
+func TestRange_Count(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	tests := []struct {
+		R    granges.Range[int]
+		Want int64
+	}{
+		{R: granges.Closed(1, 5), Want: 5},
+		{R: granges.ClosedOpen(1, 5), Want: 4},
+		{R: granges.OpenClosed(1, 5), Want: 4},
+		{R: granges.Open(1, 5), Want: 3},
+		{R: granges.Singleton(1), Want: 1},
+		{R: granges.ClosedOpen(1, 1), Want: 0},
+		{R: granges.Open(1, 2), Want: 0},
+		{R: granges.Closed(-5, 5), Want: 11},
+		{R: granges.AtLeast(math.MaxInt - 2), Want: 3},
+		{R: granges.GreaterThan(math.MaxInt), Want: 0},
+		{R: granges.AtMost(math.MinInt + 2), Want: 3},
+		{R: granges.AtLeast(0), Want: math.MaxInt64},
+		{R: granges.All[int](), Want: math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		count, err := tt.R.Count(ints)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, count, "%v.Count()", tt.R)
+	}
+
+	count, err := granges.All[uint8]().Count(granges.IntegerDomain[uint8]())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(256), count)
+
+	count, err = granges.AtMost(9).Count(naturals{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+	_, err = granges.AtLeast(9).Count(naturals{})
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+
+	_, err = granges.Invalid[int]().Count(ints)
+	assert.Error(t, err)
+}
+
+func TestRange_ToSlice(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	tests := []struct {
+		R    granges.Range[int]
+		Want []int
+	}{
+		{R: granges.Closed(1, 5), Want: []int{1, 2, 3, 4, 5}},
+		{R: granges.ClosedOpen(1, 5), Want: []int{1, 2, 3, 4}},
+		{R: granges.Open(-2, 2), Want: []int{-1, 0, 1}},
+		{R: granges.Singleton(3), Want: []int{3}},
+		{R: granges.ClosedOpen(3, 3), Want: []int{}},
+		{R: granges.Open(3, 4), Want: []int{}},
+		{R: granges.Closed(math.MaxInt-1, math.MaxInt), Want: []int{math.MaxInt - 1, math.MaxInt}},
+	}
+
+	for _, tt := range tests {
+		values, err := tt.R.ToSlice(ints)
+		assert.NoError(t, err)
+		assert.NotNil(t, values)
+		assert.Equal(t, tt.Want, values, "%v.ToSlice()", tt.R)
+		assert.Equal(t, len(values), cap(values))
+	}
+
+	for _, r := range []granges.Range[int]{granges.AtLeast(0), granges.AtMost(0), granges.All[int](), granges.Invalid[int]()} {
+		values, err := r.ToSlice(ints)
+		assert.Error(t, err, "%v.ToSlice()", r)
+		assert.Nil(t, values)
+	}
+	_, err := granges.AtLeast(0).ToSlice(ints)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+}
+
+func TestIntersectAll(t *testing.T) {
+	tests := []struct {
+		Ranges []granges.Range[int]
+		Want   granges.Range[int]
+	}{
+		{Ranges: nil, Want: granges.All[int]()},
+		{Ranges: []granges.Range[int]{granges.Closed(1, 9)}, Want: granges.Closed(1, 9)},
+		{Ranges: []granges.Range[int]{granges.Closed(1, 9), granges.Open(3, 12), granges.AtMost(7)}, Want: granges.OpenClosed(3, 7)},
+		{Ranges: []granges.Range[int]{granges.AtLeast(2), granges.LessThan(5)}, Want: granges.ClosedOpen(2, 5)},
+		{Ranges: []granges.Range[int]{granges.ClosedOpen(1, 5), granges.ClosedOpen(5, 9)}, Want: granges.ClosedOpen(5, 5)},
+		{Ranges: []granges.Range[int]{granges.ClosedOpen(1, 5), granges.Closed(5, 9), granges.Closed(0, 10)}, Want: granges.ClosedOpen(5, 5)},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.IntersectAll(tt.Ranges)
+		assert.NoError(t, err)
+		assert.True(t, tt.Want.Equal(r), "IntersectAll(%v) = %v, want %v", tt.Ranges, r, tt.Want)
+	}
+
+	for _, ranges := range [][]granges.Range[int]{
+		{granges.Closed(1, 3), granges.Closed(5, 7)},
+		{granges.Closed(1, 9), granges.Closed(2, 4), granges.Closed(5, 7)},
+		{granges.Closed(1, 9), granges.Invalid[int]()},
+	} {
+		r, err := granges.IntersectAll(ranges)
+		assert.Error(t, err, "IntersectAll(%v)", ranges)
+		assert.True(t, r.IsInvalid())
+	}
+}
+
+func TestSpanAll(t *testing.T) {
+	tests := []struct {
+		Ranges []granges.Range[int]
+		Want   granges.Range[int]
+	}{
+		{Ranges: []granges.Range[int]{granges.Closed(1, 3)}, Want: granges.Closed(1, 3)},
+		{Ranges: []granges.Range[int]{granges.Closed(1, 3), granges.Open(5, 7)}, Want: granges.ClosedOpen(1, 7)},
+		{Ranges: []granges.Range[int]{granges.Open(5, 7), granges.ClosedOpen(2, 2), granges.Closed(1, 3)}, Want: granges.ClosedOpen(1, 7)},
+		{Ranges: []granges.Range[int]{granges.Closed(1, 3), granges.AtLeast(10), granges.Singleton(-4)}, Want: granges.AtLeast(-4)},
+		{Ranges: []granges.Range[int]{granges.LessThan(0), granges.GreaterThan(0)}, Want: granges.All[int]()},
+		{Ranges: []granges.Range[int]{granges.ClosedOpen(5, 5), granges.OpenClosed(5, 5)}, Want: granges.Closed(5, 5)},
+	}
+
+	for _, tt := range tests {
+		r, err := granges.SpanAll(tt.Ranges)
+		assert.NoError(t, err)
+		assert.True(t, tt.Want.Equal(r), "SpanAll(%v) = %v, want %v", tt.Ranges, r, tt.Want)
+
+		pairwise := tt.Ranges[len(tt.Ranges)-1]
+		for i := len(tt.Ranges) - 2; i >= 0; i-- {
+			pairwise = tt.Ranges[i].Span(pairwise)
+		}
+		assert.True(t, pairwise.Equal(r), "SpanAll(%v) = %v, pairwise span is %v", tt.Ranges, r, pairwise)
+
+		reversed := slices.Clone(tt.Ranges)
+		slices.Reverse(reversed)
+		r, err = granges.SpanAll(reversed)
+		assert.NoError(t, err)
+		assert.True(t, tt.Want.Equal(r), "SpanAll(%v) = %v, want %v", reversed, r, tt.Want)
+	}
+
+	r, err := granges.SpanAll[int](nil)
+	assert.Error(t, err)
+	assert.True(t, r.IsInvalid())
+	_, err = granges.SpanAll([]granges.Range[int]{granges.Closed(1, 3), granges.Invalid[int]()})
+	assert.Error(t, err)
+	_, err = granges.SpanAll([]granges.Range[int]{granges.Invalid[int](), granges.Closed(1, 3)})
+	assert.Error(t, err)
+}
+
+func TestCompareLowerBound(t *testing.T) {
+	assert.Negative(t, granges.CompareLowerBound(granges.Closed(3, 5), granges.Open(3, 4)))
+	assert.Positive(t, granges.CompareLowerBound(granges.OpenClosed(3, 4), granges.Closed(3, 9)))
+	assert.Zero(t, granges.CompareLowerBound(granges.Closed(3, 5), granges.ClosedOpen(3, 9)))
+	assert.Negative(t, granges.CompareLowerBound(granges.AtMost(0), granges.Closed(-100, 5)))
+	assert.Zero(t, granges.CompareLowerBound(granges.LessThan(1), granges.All[int]()))
+	assert.Positive(t, granges.CompareLowerBound(granges.GreaterThan(4), granges.Closed(2, 3)))
+
+	assert.Positive(t, granges.CompareLowerBound(granges.Invalid[int](), granges.AtLeast(100)))
+	assert.Negative(t, granges.CompareLowerBound(granges.LessThan(1), granges.Invalid[int]()))
+	assert.Zero(t, granges.CompareLowerBound(granges.Invalid[int](), granges.Invalid[int]()))
+}
+
+func TestCompareUpperBound(t *testing.T) {
+	assert.Negative(t, granges.CompareUpperBound(granges.ClosedOpen(1, 3), granges.Closed(2, 3)))
+	assert.Positive(t, granges.CompareUpperBound(granges.Closed(1, 3), granges.Open(2, 3)))
+	assert.Zero(t, granges.CompareUpperBound(granges.Closed(1, 3), granges.OpenClosed(2, 3)))
+	assert.Positive(t, granges.CompareUpperBound(granges.AtLeast(0), granges.Closed(2, 300)))
+	assert.Zero(t, granges.CompareUpperBound(granges.GreaterThan(1), granges.All[int]()))
+
+	assert.Positive(t, granges.CompareUpperBound(granges.Invalid[int](), granges.All[int]()))
+	assert.Negative(t, granges.CompareUpperBound(granges.All[int](), granges.Invalid[int]()))
+}
+
+func TestCompare(t *testing.T) {
+	ranges := []granges.Range[int]{
+		granges.Invalid[int](),
+		granges.Open(1, 2),
+		granges.Closed(1, 5),
+		granges.AtLeast(1),
+		granges.ClosedOpen(1, 5),
+		granges.All[int](),
+		granges.LessThan(0),
+		granges.Singleton(3),
+		granges.ClosedOpen(3, 3),
+	}
+	slices.SortFunc(ranges, granges.Compare[int])
+	assert.EqualValues(t, "[(-∞..0) (-∞..+∞) [1..5) [1..5] [1..+∞) (1..2) [3..3) [3..3] (-∞..]", fmt.Sprint(ranges))
+
+	for _, a := range ranges {
+		for _, b := range ranges {
+			assert.Equal(t, a.Equal(b), granges.Compare(a, b) == 0, "Compare(%v, %v)", a, b)
+			assert.Equal(t, granges.Compare(a, b), -granges.Compare(b, a), "Compare(%v, %v)", a, b)
+		}
+	}
+}
+
+func TestSortRanges(t *testing.T) {
+	ranges := []granges.Range[int]{
+		granges.Closed(5, 9),
+		granges.Invalid[int](),
+		granges.AtLeast(0),
+		granges.ClosedOpen(0, 3),
+		granges.LessThan(2),
+		granges.Singleton(5),
+	}
+	granges.SortRanges(ranges)
+	assert.EqualValues(t, "[(-∞..2) [0..3) [0..+∞) [5..5] [5..9] (-∞..]", fmt.Sprint(ranges))
+	assert.True(t, slices.IsSortedFunc(ranges, granges.Compare[int]))
+
+	granges.SortRanges[int](nil)
+}
+
+func TestRange_Compare(t *testing.T) {
+	assert.Negative(t, granges.Closed(1, 3).Compare(granges.Closed(2, 3)))
+	assert.Negative(t, granges.Closed(1, 3).Compare(granges.OpenClosed(1, 2)))
+	assert.Negative(t, granges.ClosedOpen(1, 3).Compare(granges.Closed(1, 3)))
+	assert.Positive(t, granges.Closed(1, 3).Compare(granges.Closed(1, 2)))
+	assert.Zero(t, granges.Closed(1, 3).Compare(granges.Closed(1, 3)))
+	assert.Negative(t, granges.All[int]().Compare(granges.Closed(-100, 3)))
+
+	// invalid ranges sort after every valid range
+	assert.Positive(t, granges.Invalid[int]().Compare(granges.AtLeast(math.MaxInt)))
+	assert.Negative(t, granges.All[int]().Compare(granges.Invalid[int]()))
+	assert.Zero(t, granges.Invalid[int]().Compare(granges.Invalid[int]()))
+
+	sorted := []granges.Range[int]{
+		granges.LessThan(0),
+		granges.ClosedOpen(0, 5),
+		granges.Closed(0, 5),
+		granges.Open(0, 5),
+		granges.Closed(7, 9),
+		granges.Invalid[int](),
+	}
+	assert.True(t, slices.IsSortedFunc(sorted, granges.Range[int].Compare))
+	for i, r := range sorted {
+		j, found := slices.BinarySearchFunc(sorted, r, granges.Range[int].Compare)
+		assert.True(t, found)
+		assert.Equal(t, i, j)
+	}
+	j, found := slices.BinarySearchFunc(sorted, granges.OpenClosed(0, 5), granges.Range[int].Compare)
+	assert.False(t, found)
+	assert.Equal(t, 4, j)
+}
+
+func TestRange_Split(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	tests := []struct {
+		R    granges.Range[int]
+		N    int
+		Want string
+	}{
+		{R: granges.ClosedOpen(0, 100), N: 4, Want: "[[0..25) [25..50) [50..75) [75..100)]"},
+		{R: granges.ClosedOpen(0, 10), N: 3, Want: "[[0..4) [4..7) [7..10)]"},
+		{R: granges.ClosedOpen(0, 11), N: 4, Want: "[[0..3) [3..6) [6..9) [9..11)]"},
+		{R: granges.Closed(1, 10), N: 1, Want: "[[1..11)]"},
+		{R: granges.Open(0, 10), N: 2, Want: "[[1..6) [6..10)]"},
+		{R: granges.Closed(0, 1), N: 4, Want: "[[0..1) [1..2)]"},
+		{R: granges.Open(0, 4), N: math.MaxInt, Want: "[[1..2) [2..3) [3..4)]"},
+		{R: granges.ClosedOpen(0, 2), N: math.MaxInt, Want: "[[0..1) [1..2)]"},
+		{R: granges.ClosedOpen(5, 5), N: 2, Want: "[]"},
+		{R: granges.Closed(math.MaxInt-4, math.MaxInt-1), N: 3, Want: fmt.Sprintf("[[%d..%d) [%d..%d) [%d..%d)]", math.MaxInt-4, math.MaxInt-2, math.MaxInt-2, math.MaxInt-1, math.MaxInt-1, math.MaxInt)},
+	}
+
+	for _, tt := range tests {
+		parts, err := tt.R.Split(tt.N, ints)
+		assert.NoError(t, err)
+		assert.EqualValues(t, tt.Want, fmt.Sprint(parts), "%v.Split(%d)", tt.R, tt.N)
+	}
+
+	// ranges reaching the maximum value end as the split range does, rather
+	// than being unbounded above
+	parts, err := granges.Closed[int8](120, 127).Split(3, granges.IntegerDomain[int8]())
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[120..123) [123..126) [126..127]]", fmt.Sprint(parts))
+	parts, err = granges.Closed[int8](126, 127).Split(4, granges.IntegerDomain[int8]())
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[126..127) [127..127]]", fmt.Sprint(parts))
+	parts, err = granges.OpenClosed[int8](125, 127).Split(2, granges.IntegerDomain[int8]())
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[126..127) [127..127]]", fmt.Sprint(parts))
+
+	// domains without offsets are walked value by value
+	walked, err := granges.Closed(0, 9).Split(3, naturals{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "[[0..4) [4..7) [7..10)]", fmt.Sprint(walked))
+
+	_, err = granges.Closed(0, 10).Split(0, ints)
+	assert.Error(t, err)
+	_, err = granges.AtLeast(0).Split(2, ints)
+	assert.ErrorIs(t, err, granges.ErrRangeSideUnbounded)
+	_, err = granges.Invalid[int]().Split(2, ints)
+	assert.Error(t, err)
+	_, err = granges.Closed(math.MinInt, math.MaxInt-1).Split(2, ints)
+	assert.Error(t, err)
+}
@@ -0,0 +1,569 @@
+package granges
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RangeSet is a set of values of type C, represented as a collection of
+// disjoint nonempty ranges. Ranges added to the set are coalesced with the
+// members they are connected to, so that the set never holds two connected
+// ranges: adding [1..5] and then [5..10] yields the single member [1..10].
+//
+// The zero value is an empty set ready to use. Every mutation replaces the
+// members of the set instead of modifying them in place, thus a copy of a
+// RangeSet is never affected by mutations of the original.
+type RangeSet[C any] struct {
+	// ranges holds the members in ascending order, none of them is empty and
+	// no two of them are connected.
+	ranges []Range[C]
+}
+
+// NewRangeSet returns a set containing every value contained in any of the
+// given ranges.
+func NewRangeSet[C any](ranges ...Range[C]) RangeSet[C] {
+	var s RangeSet[C]
+	for _, r := range ranges {
+		s.Add(r)
+	}
+	return s
+}
+
+// Add adds every value of r to this set, coalescing r with the members it is
+// connected to. Invalid and empty ranges are ignored, and so are ranges
+// ordered by another comparator than the members of this set; see AddE.
+func (s *RangeSet[C]) Add(r Range[C]) {
+	_ = s.AddE(r)
+}
+
+// AddE adds every value of r to this set, coalescing r with the members it is
+// connected to. Invalid and empty ranges are ignored.
+//
+// ErrComparatorMismatch will be returned, leaving this set unchanged, if r is
+// ordered by another comparator than the members of this set.
+func (s *RangeSet[C]) AddE(r Range[C]) error {
+	if r.IsInvalid() || r.IsEmpty() {
+		return nil
+	}
+	if err := s.checkOrder(r); err != nil {
+		return err
+	}
+
+	// members in [i, j) are connected to r
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].upperBound.Compare(r.lowerBound) >= 0
+	})
+	j := sort.Search(len(s.ranges), func(j int) bool {
+		return s.ranges[j].lowerBound.Compare(r.upperBound) > 0
+	})
+
+	merged := r
+	if i < j {
+		merged = merged.Span(s.ranges[i]).Span(s.ranges[j-1])
+	}
+	s.ranges = slices.Concat(s.ranges[:i], []Range[C]{merged}, s.ranges[j:])
+	return nil
+}
+
+// Remove removes every value of r from this set, truncating or splitting the
+// members overlapping r. Invalid and empty ranges are ignored, and so are
+// ranges ordered by another comparator than the members of this set; see
+// RemoveE.
+func (s *RangeSet[C]) Remove(r Range[C]) {
+	_ = s.RemoveE(r)
+}
+
+// RemoveE removes every value of r from this set, truncating or splitting the
+// members overlapping r. Invalid and empty ranges are ignored.
+//
+// ErrComparatorMismatch will be returned, leaving this set unchanged, if r is
+// ordered by another comparator than the members of this set.
+func (s *RangeSet[C]) RemoveE(r Range[C]) error {
+	if r.IsInvalid() || r.IsEmpty() {
+		return nil
+	}
+	if err := s.checkOrder(r); err != nil {
+		return err
+	}
+
+	// members in [i, j) have a nonempty intersection with r
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].upperBound.Compare(r.lowerBound) > 0
+	})
+	j := sort.Search(len(s.ranges), func(j int) bool {
+		return s.ranges[j].lowerBound.Compare(r.upperBound) >= 0
+	})
+	if i == j {
+		return nil
+	}
+
+	var remains []Range[C]
+	if first := s.ranges[i]; first.lowerBound.Compare(r.lowerBound) < 0 {
+		remains = append(remains, Range[C]{lowerBound: first.lowerBound, upperBound: r.lowerBound})
+	}
+	if last := s.ranges[j-1]; r.upperBound.Compare(last.upperBound) < 0 {
+		remains = append(remains, Range[C]{lowerBound: r.upperBound, upperBound: last.upperBound})
+	}
+	s.ranges = slices.Concat(s.ranges[:i], remains, s.ranges[j:])
+	return nil
+}
+
+// TrimBelow removes every value less than point from this set, or less than
+// or equal to point if inclusive is true, truncating the member straddling
+// point. For example, trimming {[1..5], [8..10]} below 3 yields
+// {[3..5], [8..10]}, and {(3..5], [8..10]} if inclusive. The members to drop
+// are found with a binary search rather than by scanning every member.
+func (s *RangeSet[C]) TrimBelow(point C, inclusive bool) {
+	if len(s.ranges) == 0 {
+		return
+	}
+	if inclusive {
+		s.ranges = s.above(s.ranges[0].lowerBound.aboveValue(point))
+	} else {
+		s.ranges = s.above(s.ranges[0].lowerBound.belowValue(point))
+	}
+}
+
+// TrimAbove removes every value greater than point from this set, or greater
+// than or equal to point if inclusive is true, truncating the member
+// straddling point. For example, trimming {[1..5], [8..10]} above 3 yields
+// {[1..3]}, and {[1..3)} if inclusive.
+func (s *RangeSet[C]) TrimAbove(point C, inclusive bool) {
+	if len(s.ranges) == 0 {
+		return
+	}
+	if inclusive {
+		s.ranges = s.below(s.ranges[0].lowerBound.belowValue(point))
+	} else {
+		s.ranges = s.below(s.ranges[0].lowerBound.aboveValue(point))
+	}
+}
+
+// SplitAt splits this set at point into the set of its values less than
+// point and the set of its values greater than or equal to point, truncating
+// the member straddling point as Range.SplitAt does with OPEN: {[0..10]}
+// split at 4 yields {[0..4)} and {[4..10]}. The two sets are independent of
+// each other and of this set.
+func (s RangeSet[C]) SplitAt(point C) (below, above RangeSet[C]) {
+	if len(s.ranges) == 0 {
+		return RangeSet[C]{}, RangeSet[C]{}
+	}
+	cut := s.ranges[0].lowerBound.belowValue(point)
+	return RangeSet[C]{ranges: s.below(cut)}, RangeSet[C]{ranges: s.above(cut)}
+}
+
+// below returns the members of this set truncated to the values below cut.
+func (s RangeSet[C]) below(cut Cut[C]) []Range[C] {
+	// members before j start below cut
+	j := sort.Search(len(s.ranges), func(j int) bool {
+		return s.ranges[j].lowerBound.Compare(cut) >= 0
+	})
+	if j == 0 {
+		return nil
+	}
+	last := s.ranges[j-1]
+	if last.upperBound.Compare(cut) > 0 {
+		last.upperBound = cut
+	}
+	return slices.Concat(s.ranges[:j-1], []Range[C]{last})
+}
+
+// above returns the members of this set truncated to the values above cut.
+func (s RangeSet[C]) above(cut Cut[C]) []Range[C] {
+	// members from i end above cut
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].upperBound.Compare(cut) > 0
+	})
+	if i == len(s.ranges) {
+		return nil
+	}
+	first := s.ranges[i]
+	if first.lowerBound.Compare(cut) < 0 {
+		first.lowerBound = cut
+	}
+	return slices.Concat([]Range[C]{first}, s.ranges[i+1:])
+}
+
+// checkOrder returns an error if r may not be combined with the members of
+// this set because they are not ordered the same way.
+func (s RangeSet[C]) checkOrder(r Range[C]) error {
+	if len(s.ranges) == 0 || sameOrder(s.ranges[0].order(), r.order()) {
+		return nil
+	}
+	return fmt.Errorf("cannot combine %s with %s: %w", r, s, ErrComparatorMismatch)
+}
+
+// Contains returns true if value is contained in some member of this set.
+func (s RangeSet[C]) Contains(value C) bool {
+	_, ok := s.RangeContaining(value)
+	return ok
+}
+
+// RangeContaining returns the member of this set containing value, and true
+// if there is such a member. The member is found with a binary search over
+// the ordered members, in O(log n) time.
+func (s RangeSet[C]) RangeContaining(value C) (Range[C], bool) {
+	// the first member whose upper bound is not below value
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return !s.ranges[i].upperBound.IsLessThan(value)
+	})
+	if i < len(s.ranges) && s.ranges[i].Contains(value) {
+		return s.ranges[i], true
+	}
+	return Invalid[C](), false
+}
+
+// Clamp returns value if it is contained in this set, or else the value of
+// domain contained in this set nearest to value, the lesser one if two are
+// equally near. For example, in the integers, clamping 7 to {[1..3], (9..12)}
+// yields 10, and clamping 6 yields 3. Only the members next to value are
+// looked at, found with a binary search, stepping inward past their open
+// bounds with domain.
+//
+// An error will be returned if this set holds no value of domain.
+func (s RangeSet[C]) Clamp(value C, domain DiscreteDomain[C]) (C, error) {
+	// the members before i lie below value, the others above it unless the
+	// i-th one contains it
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return !s.ranges[i].upperBound.IsLessThan(value)
+	})
+	if i < len(s.ranges) && s.ranges[i].Contains(value) {
+		return value, nil
+	}
+
+	below, hasBelow := s.greatestValueBefore(i, domain)
+	above, hasAbove := s.leastValueFrom(i, domain)
+	switch {
+	case hasBelow && hasAbove:
+		if domain.Distance(below, value) <= domain.Distance(value, above) {
+			return below, nil
+		}
+		return above, nil
+	case hasBelow:
+		return below, nil
+	case hasAbove:
+		return above, nil
+	default:
+		var zero C
+		return zero, fmt.Errorf("cannot clamp %v to %s holding no value", value, s)
+	}
+}
+
+// greatestValueBefore returns the greatest value of domain contained in the
+// members of this set before the i-th one, or false if they hold none.
+func (s RangeSet[C]) greatestValueBefore(i int, domain DiscreteDomain[C]) (C, bool) {
+	for j := i - 1; j >= 0; j-- {
+		canonical := s.ranges[j].Canonical(domain)
+		if canonical.IsEmpty() {
+			continue
+		}
+		if !canonical.HasUpperBound() {
+			return domain.MaxValue()
+		}
+		return domain.Previous(canonical.upperBound.endpoint)
+	}
+	var zero C
+	return zero, false
+}
+
+// leastValueFrom returns the least value of domain contained in the members
+// of this set from the i-th one, or false if they hold none.
+func (s RangeSet[C]) leastValueFrom(i int, domain DiscreteDomain[C]) (C, bool) {
+	for j := i; j < len(s.ranges); j++ {
+		canonical := s.ranges[j].Canonical(domain)
+		if canonical.IsEmpty() {
+			continue
+		}
+		if !canonical.HasLowerBound() {
+			return domain.MinValue()
+		}
+		return canonical.lowerBound.endpoint, true
+	}
+	var zero C
+	return zero, false
+}
+
+// Encloses returns true if some member of this set encloses r, as defined by
+// Range.Encloses. In particular an empty range is enclosed when it lies
+// within the bounds of some member.
+func (s RangeSet[C]) Encloses(r Range[C]) bool {
+	if r.IsInvalid() {
+		return false
+	}
+	// the last member whose lower bound does not exceed the one of r
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].lowerBound.Compare(r.lowerBound) > 0
+	}) - 1
+	return i >= 0 && s.ranges[i].Encloses(r)
+}
+
+// EnclosesAll returns true if every member of other is enclosed by a member
+// of this set, that is if other is a subset of this set.
+func (s RangeSet[C]) EnclosesAll(other RangeSet[C]) bool {
+	for _, r := range other.ranges {
+		if !s.Encloses(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects returns true if r shares at least one value with some member of
+// this set. Unlike connectedness, merely touching a member is not enough:
+// the set {[1..3)} does not intersect [3..5], and no set intersects an empty
+// range, nor a range ordered by another comparator than its members.
+func (s RangeSet[C]) Intersects(r Range[C]) bool {
+	if r.IsInvalid() || r.IsEmpty() || s.checkOrder(r) != nil {
+		return false
+	}
+	// the first member reaching above the lower bound of r
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].upperBound.Compare(r.lowerBound) > 0
+	})
+	return i < len(s.ranges) && s.ranges[i].lowerBound.Compare(r.upperBound) < 0
+}
+
+// IntersectsSet returns true if this set shares at least one value with
+// other. As for Intersects, merely touching is not enough, and sets ordered by
+// different comparators never intersect. The spans of the sets are compared
+// first, then the members of both sets are walked together until two of them
+// overlap or either set runs out of members, without building the
+// intersection.
+func (s RangeSet[C]) IntersectsSet(other RangeSet[C]) bool {
+	if len(s.ranges) == 0 || len(other.ranges) == 0 || s.checkOrder(other.ranges[0]) != nil {
+		return false
+	}
+	if s.ranges[0].lowerBound.Compare(other.ranges[len(other.ranges)-1].upperBound) >= 0 ||
+		other.ranges[0].lowerBound.Compare(s.ranges[len(s.ranges)-1].upperBound) >= 0 {
+		return false
+	}
+
+	for i, j := 0, 0; i < len(s.ranges) && j < len(other.ranges); {
+		a, b := s.ranges[i], other.ranges[j]
+		if a.lowerBound.Compare(b.upperBound) < 0 && b.lowerBound.Compare(a.upperBound) < 0 {
+			return true
+		}
+		// the member ending first overlaps no further member of the other set
+		if a.upperBound.Compare(b.upperBound) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return false
+}
+
+// SubRangeSet returns the set of values of this set contained in view, that
+// is the intersection of every member with view. Members outside view are
+// dropped, and the members straddling its bounds are clipped.
+//
+// For example, restricting {[1..4], [6..10]} to [3..7] yields {[3..4], [6..7]}.
+// The empty set is returned if view is ordered by another comparator than the
+// members of this set.
+func (s RangeSet[C]) SubRangeSet(view Range[C]) RangeSet[C] {
+	if view.IsInvalid() || view.IsEmpty() || s.checkOrder(view) != nil {
+		return RangeSet[C]{}
+	}
+
+	// members in [i, j) have a nonempty intersection with view
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].upperBound.Compare(view.lowerBound) > 0
+	})
+	j := sort.Search(len(s.ranges), func(j int) bool {
+		return s.ranges[j].lowerBound.Compare(view.upperBound) >= 0
+	})
+
+	ranges := make([]Range[C], 0, j-i)
+	for _, r := range s.ranges[i:j] {
+		ranges = append(ranges, r.Intersection(view))
+	}
+	return RangeSet[C]{ranges: ranges}
+}
+
+// Complement returns the set of all values not contained in this set. The
+// complement of the empty set is the set holding All, and the other way
+// round.
+func (s RangeSet[C]) Complement() RangeSet[C] {
+	complement := make([]Range[C], 0, len(s.ranges)+1)
+	lowerBound := Cut[C]{cutType: BelowAll}
+	if len(s.ranges) > 0 {
+		lowerBound = s.ranges[0].lowerBound.belowAll()
+	} else {
+		lowerBound.order, _ = naturalOrderOf[C]()
+	}
+	for _, r := range s.ranges {
+		if lowerBound.Compare(r.lowerBound) < 0 {
+			complement = append(complement, Range[C]{lowerBound: lowerBound, upperBound: r.lowerBound})
+		}
+		lowerBound = r.upperBound
+	}
+	if upperBound := lowerBound.aboveAll(); lowerBound.Compare(upperBound) < 0 {
+		complement = append(complement, Range[C]{lowerBound: lowerBound, upperBound: upperBound})
+	}
+	return RangeSet[C]{ranges: complement}
+}
+
+// IsEmpty returns true if this set contains no values.
+func (s RangeSet[C]) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+// AsRanges returns the members of this set in ascending order. The members
+// are disjoint, nonempty, and no two of them are connected.
+func (s RangeSet[C]) AsRanges() []Range[C] {
+	return slices.Clone(s.ranges)
+}
+
+// Boundaries returns the cuts bounding the members of this set in ascending
+// order, the lower and upper bound of each member in turn, so that n members
+// have 2n boundaries. Unlike the endpoints of AsRanges, the cuts keep the
+// bound types: the boundaries of {[1..3), (5..+∞)} are the cuts below 1,
+// below 3, above 5 and above all values.
+func (s RangeSet[C]) Boundaries() []Cut[C] {
+	boundaries := make([]Cut[C], 0, 2*len(s.ranges))
+	for _, r := range s.ranges {
+		boundaries = append(boundaries, r.lowerBound, r.upperBound)
+	}
+	return boundaries
+}
+
+// BoundaryEndpoints returns the least and greatest values of domain held by
+// each member of this set in ascending order, stepping inward past open
+// bounds: the boundary endpoints of {[1..3), (5..8]} in the integers are 1,
+// 2, 6 and 8. The sides of the members unbounded in that direction are
+// skipped, and so are the members holding no value of domain.
+func (s RangeSet[C]) BoundaryEndpoints(domain DiscreteDomain[C]) []C {
+	endpoints := make([]C, 0, 2*len(s.ranges))
+	for _, r := range s.ranges {
+		if r.Canonical(domain).IsEmpty() {
+			continue
+		}
+		switch r.lowerBound.cutType {
+		case BelowValue:
+			endpoints = append(endpoints, r.lowerBound.endpoint)
+		case AboveValue:
+			if next, ok := domain.Next(r.lowerBound.endpoint); ok {
+				endpoints = append(endpoints, next)
+			}
+		}
+		switch r.upperBound.cutType {
+		case BelowValue:
+			if previous, ok := domain.Previous(r.upperBound.endpoint); ok {
+				endpoints = append(endpoints, previous)
+			}
+		case AboveValue:
+			endpoints = append(endpoints, r.upperBound.endpoint)
+		}
+	}
+	return endpoints
+}
+
+func (s RangeSet[C]) String() string {
+	members := make([]string, len(s.ranges))
+	for i, r := range s.ranges {
+		members[i] = r.String()
+	}
+	return "{" + strings.Join(members, ", ") + "}"
+}
+
+// Gaps returns the maximal ranges within the range within that hold no value
+// of any of ranges, in ascending order. The ranges are coalesced first, so
+// they may be given in any order and may overlap; invalid and empty ranges
+// cover nothing.
+//
+// Bound types are flipped at the seams, so that a gap holds exactly the
+// values left uncovered: the gaps of [0..2) and [5..7) within [0..10) are
+// [2..5) and [7..10), while [0..2) and (2..5] leave the singleton [2..2]
+// uncovered, and [0..2) and [2..5] leave nothing. Nil is returned if within
+// is invalid.
+func Gaps[C any](ranges []Range[C], within Range[C]) []Range[C] {
+	if within.IsInvalid() {
+		return nil
+	}
+	return NewRangeSet(ranges...).Complement().SubRangeSet(within).AsRanges()
+}
+
+// Coalesce merges the connected ranges of ranges, returning the minimal
+// disjoint ranges holding the same values, in ascending order. For example,
+// [1..3], [2..5] and [7..9] coalesce into [1..5] and [7..9], and the adjacent
+// [1..3) and [3..5) into [1..5). It is the one-shot counterpart of building a
+// RangeSet: ranges may be given in any order, and invalid and empty ranges are
+// dropped.
+func Coalesce[C any](ranges []Range[C]) []Range[C] {
+	return NewRangeSet(ranges...).AsRanges()
+}
+
+// UnionAll returns the set of values contained in any of sets. The members of
+// the sets, which are already in ascending order, are merged through a heap
+// keyed by their lower bounds and coalesced as they come out, so that uniting
+// k sets of n members in all takes O(n log k) rather than the O(n·k) of adding
+// the sets to one another in turn.
+//
+// None of sets is modified. Empty sets are skipped, and so are sets ordered by
+// another comparator than the first nonempty one, just as Add ignores ranges
+// of another ordering.
+func UnionAll[C any](sets []RangeSet[C]) RangeSet[C] {
+	var queue memberQueue[C]
+	total := 0
+	for _, s := range sets {
+		if len(s.ranges) == 0 {
+			continue
+		}
+		if len(queue) > 0 && !sameOrder(queue[0][0].order(), s.ranges[0].order()) {
+			continue
+		}
+		queue = append(queue, s.ranges)
+		total += len(s.ranges)
+	}
+	heap.Init(&queue)
+
+	ranges := make([]Range[C], 0, total)
+	for len(queue) > 0 {
+		r := queue[0][0]
+		if rest := queue[0][1:]; len(rest) > 0 {
+			queue[0] = rest
+			heap.Fix(&queue, 0)
+		} else {
+			heap.Pop(&queue)
+		}
+
+		if last := len(ranges) - 1; last >= 0 && ranges[last].upperBound.Compare(r.lowerBound) >= 0 {
+			if ranges[last].upperBound.Compare(r.upperBound) < 0 {
+				ranges[last].upperBound = r.upperBound
+			}
+			continue
+		}
+		ranges = append(ranges, r)
+	}
+	return RangeSet[C]{ranges: ranges}
+}
+
+// memberQueue is a heap of the members left to merge of each set, ordered by
+// the lower bound of their first member, for UnionAll.
+type memberQueue[C any] [][]Range[C]
+
+func (q memberQueue[C]) Len() int {
+	return len(q)
+}
+
+func (q memberQueue[C]) Less(i, j int) bool {
+	return q[i][0].lowerBound.Compare(q[j][0].lowerBound) < 0
+}
+
+func (q memberQueue[C]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *memberQueue[C]) Push(x any) {
+	*q = append(*q, x.([]Range[C]))
+}
+
+func (q *memberQueue[C]) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
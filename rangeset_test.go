@@ -0,0 +1,656 @@
+package granges_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestRangeSet_Add(t *testing.T) {
+	var s granges.RangeSet[int]
+	assert.True(t, s.IsEmpty())
+	assert.EqualValues(t, "{}", s.String())
+
+	s.Add(granges.Closed(1, 5))
+	s.Add(granges.Closed(5, 10))
+	assert.EqualValues(t, "{[1..10]}", s.String())
+
+	s.Add(granges.Open(20, 30))
+	s.Add(granges.ClosedOpen(12, 15))
+	assert.EqualValues(t, "{[1..10], [12..15), (20..30)}", s.String())
+
+	// touching ranges are coalesced even if they share no value
+	s.Add(granges.ClosedOpen(15, 18))
+	s.Add(granges.OpenClosed(18, 20))
+	assert.EqualValues(t, "{[1..10], [12..18), (18..30)}", s.String())
+
+	// enclosed ranges change nothing
+	s.Add(granges.Closed(2, 3))
+	assert.EqualValues(t, "{[1..10], [12..18), (18..30)}", s.String())
+
+	// a range bridging several members
+	s.Add(granges.Closed(9, 25))
+	assert.EqualValues(t, "{[1..30)}", s.String())
+
+	// empty and invalid ranges are ignored
+	s.Add(granges.ClosedOpen(50, 50))
+	s.Add(granges.Invalid[int]())
+	assert.EqualValues(t, "{[1..30)}", s.String())
+
+	s.Add(granges.AtLeast(30))
+	s.Add(granges.LessThan(-10))
+	assert.EqualValues(t, "{(-∞..-10), [1..+∞)}", s.String())
+
+	s.Add(granges.All[int]())
+	assert.EqualValues(t, "{(-∞..+∞)}", s.String())
+}
+
+func TestRangeSet_Remove(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 10), granges.Closed(20, 30))
+
+	s.Remove(granges.Closed(3, 5))
+	assert.EqualValues(t, "{[1..3), (5..10], [20..30]}", s.String())
+
+	s.Remove(granges.Open(8, 25))
+	assert.EqualValues(t, "{[1..3), (5..8], [25..30]}", s.String())
+
+	// removing what is not there changes nothing
+	s.Remove(granges.ClosedOpen(3, 5))
+	s.Remove(granges.Closed(40, 50))
+	s.Remove(granges.ClosedOpen(7, 7))
+	assert.EqualValues(t, "{[1..3), (5..8], [25..30]}", s.String())
+
+	// boundary points
+	s.Remove(granges.Singleton(1))
+	s.Remove(granges.Singleton(30))
+	assert.EqualValues(t, "{(1..3), (5..8], [25..30)}", s.String())
+
+	s.Remove(granges.AtLeast(6))
+	assert.EqualValues(t, "{(1..3), (5..6)}", s.String())
+
+	s.Remove(granges.All[int]())
+	assert.True(t, s.IsEmpty())
+}
+
+func TestRangeSet_TrimBelow(t *testing.T) {
+	tests := []struct {
+		Point     int
+		Inclusive bool
+		Want      string
+	}{
+		{Point: 3, Inclusive: false, Want: "{[3..5], [8..10], [12..+∞)}"},
+		{Point: 3, Inclusive: true, Want: "{(3..5], [8..10], [12..+∞)}"},
+		{Point: 5, Inclusive: false, Want: "{[5..5], [8..10], [12..+∞)}"},
+		{Point: 5, Inclusive: true, Want: "{[8..10], [12..+∞)}"},
+		{Point: 6, Inclusive: true, Want: "{[8..10], [12..+∞)}"},
+		{Point: 8, Inclusive: false, Want: "{[8..10], [12..+∞)}"},
+		{Point: -100, Inclusive: true, Want: "{[1..5], [8..10], [12..+∞)}"},
+		{Point: 100, Inclusive: false, Want: "{[100..+∞)}"},
+	}
+	for _, tt := range tests {
+		s := granges.NewRangeSet(granges.Closed(1, 5), granges.Closed(8, 10), granges.AtLeast(12))
+		s.TrimBelow(tt.Point, tt.Inclusive)
+		assert.EqualValues(t, tt.Want, s.String(), "TrimBelow(%d, %t)", tt.Point, tt.Inclusive)
+	}
+
+	s := granges.NewRangeSet(granges.Closed(1, 5))
+	copied := s
+	s.TrimBelow(10, false)
+	assert.True(t, s.IsEmpty())
+	assert.EqualValues(t, "{[1..5]}", copied.String())
+}
+
+func TestRangeSet_TrimAbove(t *testing.T) {
+	tests := []struct {
+		Point     int
+		Inclusive bool
+		Want      string
+	}{
+		{Point: 3, Inclusive: false, Want: "{(-∞..-2), [1..3]}"},
+		{Point: 3, Inclusive: true, Want: "{(-∞..-2), [1..3)}"},
+		{Point: 1, Inclusive: false, Want: "{(-∞..-2), [1..1]}"},
+		{Point: 1, Inclusive: true, Want: "{(-∞..-2)}"},
+		{Point: 10, Inclusive: false, Want: "{(-∞..-2), [1..5], [8..10]}"},
+		{Point: 10, Inclusive: true, Want: "{(-∞..-2), [1..5], [8..10)}"},
+		{Point: -2, Inclusive: false, Want: "{(-∞..-2)}"},
+		{Point: -100, Inclusive: true, Want: "{(-∞..-100)}"},
+	}
+	for _, tt := range tests {
+		s := granges.NewRangeSet(granges.LessThan(-2), granges.Closed(1, 5), granges.Closed(8, 10))
+		s.TrimAbove(tt.Point, tt.Inclusive)
+		assert.EqualValues(t, tt.Want, s.String(), "TrimAbove(%d, %t)", tt.Point, tt.Inclusive)
+	}
+
+	var empty granges.RangeSet[int]
+	empty.TrimAbove(1, true)
+	assert.True(t, empty.IsEmpty())
+}
+
+func TestRangeSet_SplitAt(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(0, 10), granges.Open(12, 15))
+	below, above := s.SplitAt(4)
+	assert.EqualValues(t, "{[0..4)}", below.String())
+	assert.EqualValues(t, "{[4..10], (12..15)}", above.String())
+
+	below, above = s.SplitAt(11)
+	assert.EqualValues(t, "{[0..10]}", below.String())
+	assert.EqualValues(t, "{(12..15)}", above.String())
+
+	below, above = s.SplitAt(0)
+	assert.True(t, below.IsEmpty())
+	assert.EqualValues(t, "{[0..10], (12..15)}", above.String())
+
+	// the sets are independent of one another
+	below.Add(granges.Closed(-5, 0))
+	above.Remove(granges.Closed(0, 100))
+	assert.EqualValues(t, "{[0..10], (12..15)}", s.String())
+
+	below, above = granges.RangeSet[int]{}.SplitAt(1)
+	assert.True(t, below.IsEmpty())
+	assert.True(t, above.IsEmpty())
+}
+
+func TestRangeSet_comparatorMismatch(t *testing.T) {
+	var s granges.RangeSet[time.Time]
+	assert.NoError(t, s.AddE(byTime.Closed(day(0), day(2))))
+
+	// a range ordered naturally is not combined with members ordered by a
+	// comparator
+	assert.ErrorIs(t, s.AddE(granges.Closed(day(1), day(3))), granges.ErrComparatorMismatch)
+	assert.ErrorIs(t, s.RemoveE(granges.Closed(day(1), day(3))), granges.ErrComparatorMismatch)
+	s.Add(granges.Closed(day(1), day(3)))
+	s.Remove(granges.Closed(day(1), day(3)))
+	assert.EqualValues(t, []granges.Range[time.Time]{byTime.Closed(day(0), day(2))}, s.AsRanges())
+	assert.False(t, s.Intersects(granges.Closed(day(1), day(3))))
+	assert.True(t, s.SubRangeSet(granges.Closed(day(1), day(3))).IsEmpty())
+
+	assert.NoError(t, s.AddE(byTime.Closed(day(1), day(3))))
+	assert.NoError(t, s.RemoveE(byTime.Open(day(1), day(2))))
+	assert.EqualValues(t, []granges.Range[time.Time]{byTime.Closed(day(0), day(1)), byTime.Closed(day(2), day(3))}, s.AsRanges())
+
+	// the natural orderings of a type go together
+	var ints granges.RangeSet[int]
+	assert.NoError(t, ints.AddE(granges.Closed(1, 5)))
+	assert.NoError(t, ints.AddE(granges.Closed(3, 7)))
+	assert.EqualValues(t, "{[1..7]}", ints.String())
+}
+
+func TestRangeSet_Contains(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 3), granges.Open(5, 7), granges.AtLeast(10))
+	for v, want := range map[int]bool{
+		0: false, 1: true, 2: true, 3: true, 4: false, 5: false,
+		6: true, 7: false, 9: false, 10: true, 1000: true,
+	} {
+		if get := s.Contains(v); get != want {
+			t.Errorf("%v.Contains(%d) = %v, want %v", s, v, get, want)
+		}
+	}
+
+	var empty granges.RangeSet[int]
+	assert.False(t, empty.Contains(0))
+}
+
+func TestRangeSet_Clamp(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	s := granges.NewRangeSet(granges.Closed(1, 3), granges.Open(9, 12), granges.OpenClosed(20, 21))
+	tests := []struct {
+		Value int
+		Want  int
+	}{
+		{Value: 2, Want: 2},
+		{Value: 11, Want: 11},
+		{Value: -100, Want: 1},
+		{Value: 6, Want: 3},
+		{Value: 7, Want: 10},
+		{Value: 16, Want: 11}, // ties are broken downward
+		{Value: 17, Want: 21},
+		{Value: 12, Want: 11},
+		{Value: 20, Want: 21},
+		{Value: 100, Want: 21},
+	}
+	for _, tt := range tests {
+		got, err := s.Clamp(tt.Value, ints)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, got, "Clamp(%d)", tt.Value)
+	}
+
+	// members holding no value of the domain are skipped
+	sparse := granges.NewRangeSet(granges.Closed(0, 1), granges.Open(4, 5), granges.Closed(10, 10))
+	got, err := sparse.Clamp(5, ints)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got)
+	got, err = sparse.Clamp(6, ints)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, got)
+
+	// unbounded members reach the limits of the domain
+	small, err := granges.NewRangeSet(granges.AtMost[int8](-100)).Clamp(0, granges.IntegerDomain[int8]())
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-100), small)
+	small, err = granges.NewRangeSet(granges.GreaterThan[int8](100)).Clamp(0, granges.IntegerDomain[int8]())
+	assert.NoError(t, err)
+	assert.Equal(t, int8(101), small)
+
+	_, err = granges.RangeSet[int]{}.Clamp(1, ints)
+	assert.Error(t, err)
+	_, err = granges.NewRangeSet(granges.Open(4, 5)).Clamp(1, ints)
+	assert.Error(t, err)
+}
+
+func TestRangeSet_AsRanges(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(5, 7), granges.Closed(1, 3))
+	ranges := s.AsRanges()
+	assert.Len(t, ranges, 2)
+	assert.True(t, granges.Closed(1, 3).Equal(ranges[0]))
+	assert.True(t, granges.Closed(5, 7).Equal(ranges[1]))
+
+	// the returned slice is a copy, and copies of the set are independent
+	ranges[0] = granges.All[int]()
+	cp := s
+	cp.Add(granges.Closed(3, 5))
+	assert.EqualValues(t, "{[1..3], [5..7]}", s.String())
+	assert.EqualValues(t, "{[1..7]}", cp.String())
+
+	var empty granges.RangeSet[int]
+	assert.Empty(t, empty.AsRanges())
+}
+
+func TestRangeSet_Boundaries(t *testing.T) {
+	s := granges.NewRangeSet(granges.ClosedOpen(1, 3), granges.OpenClosed(5, 8), granges.GreaterThan(10))
+	boundaries := s.Boundaries()
+	assert.Len(t, boundaries, 6)
+	assert.EqualValues(t, "[1 3) (5 8] (10 +∞)", fmt.Sprintf("%s %s %s %s %s %s",
+		boundaries[0].DescribeAsLowerBound(), boundaries[1].DescribeAsUpperBound(),
+		boundaries[2].DescribeAsLowerBound(), boundaries[3].DescribeAsUpperBound(),
+		boundaries[4].DescribeAsLowerBound(), boundaries[5].DescribeAsUpperBound()))
+	for i := 1; i < len(boundaries); i++ {
+		assert.Negative(t, boundaries[i-1].Compare(boundaries[i]))
+	}
+
+	assert.Empty(t, granges.RangeSet[int]{}.Boundaries())
+	assert.Len(t, granges.NewRangeSet(granges.All[int]()).Boundaries(), 2)
+}
+
+func TestRangeSet_BoundaryEndpoints(t *testing.T) {
+	ints := granges.IntegerDomain[int]()
+	s := granges.NewRangeSet(granges.LessThan(-3), granges.ClosedOpen(1, 3), granges.OpenClosed(5, 8), granges.GreaterThan(10))
+	assert.EqualValues(t, []int{-4, 1, 2, 6, 8, 11}, s.BoundaryEndpoints(ints))
+
+	// members holding no value of the domain are skipped
+	s = granges.NewRangeSet(granges.Open(1, 2), granges.Singleton(4), granges.Open(6, 8))
+	assert.EqualValues(t, []int{4, 4, 7, 7}, s.BoundaryEndpoints(ints))
+
+	int8s := granges.IntegerDomain[int8]()
+	assert.EqualValues(t, []int8{-128, 127}, granges.NewRangeSet(granges.Closed[int8](-128, 127)).BoundaryEndpoints(int8s))
+	assert.Empty(t, granges.NewRangeSet(granges.All[int8]()).BoundaryEndpoints(int8s))
+	assert.Empty(t, granges.RangeSet[int]{}.BoundaryEndpoints(ints))
+}
+
+func TestRangeSet_Complement(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 5), granges.Closed(10, 15))
+	assert.EqualValues(t, "{(-∞..1), (5..10), (15..+∞)}", s.Complement().String())
+	assert.EqualValues(t, s.String(), s.Complement().Complement().String())
+
+	s = granges.NewRangeSet(granges.LessThan(1), granges.OpenClosed(5, 10), granges.AtLeast(15))
+	assert.EqualValues(t, "{[1..5], (10..15)}", s.Complement().String())
+
+	s = granges.NewRangeSet(granges.ClosedOpen(1, 5), granges.OpenClosed(5, 10))
+	assert.EqualValues(t, "{(-∞..1), [5..5], (10..+∞)}", s.Complement().String())
+
+	var empty granges.RangeSet[int]
+	assert.EqualValues(t, "{(-∞..+∞)}", empty.Complement().String())
+
+	all := granges.NewRangeSet(granges.All[int]())
+	assert.True(t, all.Complement().IsEmpty())
+
+	for v := -5; v <= 20; v++ {
+		assert.NotEqual(t, s.Contains(v), s.Complement().Contains(v))
+	}
+}
+
+func TestRangeSet_Encloses(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 5), granges.Open(10, 15), granges.AtLeast(20))
+
+	tests := []struct {
+		R    granges.Range[int]
+		Want bool
+	}{
+		{R: granges.Closed(1, 5), Want: true},
+		{R: granges.Closed(2, 3), Want: true},
+		{R: granges.ClosedOpen(3, 3), Want: true},
+		{R: granges.OpenClosed(5, 5), Want: true},
+		{R: granges.Open(10, 15), Want: true},
+		{R: granges.Closed(10, 12), Want: false},
+		{R: granges.Closed(3, 12), Want: false},
+		{R: granges.ClosedOpen(7, 7), Want: false},
+		{R: granges.AtLeast(25), Want: true},
+		{R: granges.AtLeast(19), Want: false},
+		{R: granges.AtMost(3), Want: false},
+		{R: granges.Invalid[int](), Want: false},
+	}
+	for _, tt := range tests {
+		if get := s.Encloses(tt.R); get != tt.Want {
+			t.Errorf("%v.Encloses(%v) = %v, want %v", s, tt.R, get, tt.Want)
+		}
+	}
+
+	var empty granges.RangeSet[int]
+	assert.False(t, empty.Encloses(granges.ClosedOpen(1, 1)))
+}
+
+func TestRangeSet_EnclosesAll(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 5), granges.Open(10, 15), granges.AtLeast(20))
+
+	assert.True(t, s.EnclosesAll(s))
+	assert.True(t, s.EnclosesAll(granges.RangeSet[int]{}))
+	assert.True(t, s.EnclosesAll(granges.NewRangeSet(granges.Closed(2, 3), granges.Closed(11, 12), granges.Singleton(100))))
+	assert.False(t, s.EnclosesAll(granges.NewRangeSet(granges.Closed(2, 3), granges.Closed(10, 12))))
+	assert.False(t, granges.RangeSet[int]{}.EnclosesAll(s))
+}
+
+func TestRangeSet_Intersects(t *testing.T) {
+	s := granges.NewRangeSet(granges.LessThan(0), granges.ClosedOpen(1, 3), granges.Open(5, 7), granges.AtLeast(10))
+
+	tests := []struct {
+		R    granges.Range[int]
+		Want bool
+	}{
+		{R: granges.Closed(-5, -1), Want: true},
+		{R: granges.Closed(0, 0), Want: false},
+		{R: granges.Closed(-1, 1), Want: true},
+		{R: granges.Closed(3, 5), Want: false},
+		{R: granges.OpenClosed(2, 5), Want: true},
+		{R: granges.Closed(2, 2), Want: true},
+		{R: granges.Closed(7, 9), Want: false},
+		{R: granges.Open(4, 6), Want: true},
+		{R: granges.ClosedOpen(9, 10), Want: false},
+		{R: granges.Closed(9, 10), Want: true},
+		{R: granges.GreaterThan(100), Want: true},
+		{R: granges.All[int](), Want: true},
+		{R: granges.ClosedOpen(2, 2), Want: false},
+		{R: granges.Invalid[int](), Want: false},
+	}
+	for _, tt := range tests {
+		if get := s.Intersects(tt.R); get != tt.Want {
+			t.Errorf("%v.Intersects(%v) = %v, want %v", s, tt.R, get, tt.Want)
+		}
+	}
+
+	var empty granges.RangeSet[int]
+	assert.False(t, empty.Intersects(granges.All[int]()))
+}
+
+func TestRangeSet_IntersectsSet(t *testing.T) {
+	s := granges.NewRangeSet(granges.LessThan(0), granges.ClosedOpen(1, 3), granges.Open(5, 7), granges.Closed(10, 12))
+
+	tests := []struct {
+		Other granges.RangeSet[int]
+		Want  bool
+	}{
+		{Other: granges.NewRangeSet(granges.Closed(-5, -1)), Want: true},
+		{Other: granges.NewRangeSet(granges.Closed(3, 5), granges.Closed(7, 9)), Want: false},
+		{Other: granges.NewRangeSet(granges.Closed(0, 0), granges.Closed(3, 5), granges.Closed(7, 9), granges.Open(9, 10)), Want: false},
+		{Other: granges.NewRangeSet(granges.Closed(3, 5), granges.Closed(7, 9), granges.Closed(12, 20)), Want: true},
+		{Other: granges.NewRangeSet(granges.Closed(2, 2)), Want: true},
+		{Other: granges.NewRangeSet(granges.Open(4, 6)), Want: true},
+		// disjoint spans
+		{Other: granges.NewRangeSet(granges.OpenClosed(12, 13), granges.AtLeast(20)), Want: false},
+		{Other: granges.NewRangeSet(granges.Singleton(12)), Want: true},
+		{Other: granges.NewRangeSet(granges.All[int]()), Want: true},
+		{Other: granges.RangeSet[int]{}, Want: false},
+	}
+	for _, tt := range tests {
+		if get := s.IntersectsSet(tt.Other); get != tt.Want {
+			t.Errorf("%v.IntersectsSet(%v) = %v, want %v", s, tt.Other, get, tt.Want)
+		}
+		if get := tt.Other.IntersectsSet(s); get != tt.Want {
+			t.Errorf("%v.IntersectsSet(%v) = %v, want %v", tt.Other, s, get, tt.Want)
+		}
+	}
+
+	days := granges.NewRangeSet(byTime.Closed(day(0), day(2)))
+	assert.True(t, days.IntersectsSet(granges.NewRangeSet(byTime.Closed(day(1), day(3)))))
+	assert.False(t, days.IntersectsSet(granges.NewRangeSet(granges.Closed(day(1), day(3)))))
+}
+
+func TestRangeSet_SubRangeSet(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 4), granges.Closed(6, 10))
+
+	tests := []struct {
+		View granges.Range[int]
+		Want string
+	}{
+		{View: granges.Closed(3, 7), Want: "{[3..4], [6..7]}"},
+		{View: granges.Open(3, 7), Want: "{(3..4], [6..7)}"},
+		{View: granges.Open(4, 6), Want: "{}"},
+		{View: granges.Closed(4, 6), Want: "{[4..4], [6..6]}"},
+		{View: granges.Closed(0, 20), Want: "{[1..4], [6..10]}"},
+		{View: granges.AtLeast(2), Want: "{[2..4], [6..10]}"},
+		{View: granges.LessThan(8), Want: "{[1..4], [6..8)}"},
+		{View: granges.All[int](), Want: "{[1..4], [6..10]}"},
+		{View: granges.Closed(11, 20), Want: "{}"},
+		{View: granges.ClosedOpen(2, 2), Want: "{}"},
+		{View: granges.Invalid[int](), Want: "{}"},
+	}
+	for _, tt := range tests {
+		assert.EqualValues(t, tt.Want, s.SubRangeSet(tt.View).String(), "SubRangeSet(%v)", tt.View)
+	}
+
+	all := granges.NewRangeSet(granges.All[int]())
+	assert.EqualValues(t, "{(2..5]}", all.SubRangeSet(granges.OpenClosed(2, 5)).String())
+	assert.EqualValues(t, "{[1..4], [6..10]}", s.String())
+}
+
+func TestRangeSet_RangeContaining(t *testing.T) {
+	s := granges.NewRangeSet(granges.Closed(1, 3), granges.Open(5, 7), granges.ClosedOpen(8, 9), granges.GreaterThan(10))
+
+	tests := []struct {
+		Value int
+		Want  granges.Range[int]
+		OK    bool
+	}{
+		{Value: 0, OK: false},
+		{Value: 1, Want: granges.Closed(1, 3), OK: true},
+		{Value: 3, Want: granges.Closed(1, 3), OK: true},
+		{Value: 5, OK: false},
+		{Value: 6, Want: granges.Open(5, 7), OK: true},
+		{Value: 7, OK: false},
+		{Value: 8, Want: granges.ClosedOpen(8, 9), OK: true},
+		{Value: 9, OK: false},
+		{Value: 10, OK: false},
+		{Value: 11, Want: granges.GreaterThan(10), OK: true},
+	}
+	for _, tt := range tests {
+		r, ok := s.RangeContaining(tt.Value)
+		assert.EqualValues(t, tt.OK, ok, "RangeContaining(%d)", tt.Value)
+		if tt.OK {
+			assert.True(t, tt.Want.Equal(r), "RangeContaining(%d) = %v, want %v", tt.Value, r, tt.Want)
+		} else {
+			assert.True(t, r.IsInvalid())
+		}
+	}
+}
+
+func TestGaps(t *testing.T) {
+	tests := []struct {
+		Ranges []granges.Range[int]
+		Within granges.Range[int]
+		Want   string
+	}{
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(0, 2), granges.ClosedOpen(5, 7)},
+			Within: granges.ClosedOpen(0, 10),
+			Want:   "[[2..5) [7..10)]",
+		},
+		// unsorted and overlapping ranges are coalesced first
+		{
+			Ranges: []granges.Range[int]{granges.Closed(6, 8), granges.Closed(1, 3), granges.Closed(2, 4)},
+			Within: granges.Closed(0, 10),
+			Want:   "[[0..1) (4..6) (8..10]]",
+		},
+		// adjacent ranges sharing no value leave their seam uncovered
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(0, 2), granges.OpenClosed(2, 5)},
+			Within: granges.Closed(0, 5),
+			Want:   "[[2..2]]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(0, 2), granges.Closed(2, 5)},
+			Within: granges.Closed(0, 5),
+			Want:   "[]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.Closed(0, 2), granges.OpenClosed(2, 5)},
+			Within: granges.Closed(0, 5),
+			Want:   "[]",
+		},
+		// the bounds of the window are kept, even where they meet a range
+		{
+			Ranges: []granges.Range[int]{granges.Open(0, 5)},
+			Within: granges.Closed(0, 5),
+			Want:   "[[0..0] [5..5]]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.Closed(-5, 0)},
+			Within: granges.Open(0, 5),
+			Want:   "[(0..5)]",
+		},
+		// ranges outside the window, empty ranges and invalid ones are ignored
+		{
+			Ranges: []granges.Range[int]{granges.Closed(20, 30), granges.ClosedOpen(3, 3), granges.Invalid[int]()},
+			Within: granges.Closed(0, 10),
+			Want:   "[[0..10]]",
+		},
+		{
+			Ranges: nil,
+			Within: granges.ClosedOpen(0, 10),
+			Want:   "[[0..10)]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(0, 2)},
+			Within: granges.All[int](),
+			Want:   "[(-∞..0) [2..+∞)]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.All[int]()},
+			Within: granges.Closed(0, 10),
+			Want:   "[]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.Closed(0, 2)},
+			Within: granges.ClosedOpen(5, 5),
+			Want:   "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.EqualValues(t, tt.Want, fmt.Sprint(granges.Gaps(tt.Ranges, tt.Within)))
+	}
+
+	assert.Nil(t, granges.Gaps([]granges.Range[int]{granges.Closed(0, 2)}, granges.Invalid[int]()))
+}
+
+func TestCoalesce(t *testing.T) {
+	tests := []struct {
+		Ranges []granges.Range[int]
+		Want   string
+	}{
+		{
+			Ranges: []granges.Range[int]{granges.Closed(1, 3), granges.Closed(2, 5), granges.Closed(7, 9)},
+			Want:   "[[1..5] [7..9]]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(3, 5), granges.ClosedOpen(1, 3)},
+			Want:   "[[1..5)]",
+		},
+		// ranges sharing no value stay apart
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(1, 3), granges.OpenClosed(3, 5)},
+			Want:   "[[1..3) (3..5]]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.AtLeast(8), granges.Closed(1, 2), granges.LessThan(0), granges.Singleton(9)},
+			Want:   "[(-∞..0) [1..2] [8..+∞)]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.ClosedOpen(4, 4), granges.Invalid[int](), granges.Singleton(1)},
+			Want:   "[[1..1]]",
+		},
+		{
+			Ranges: []granges.Range[int]{granges.Closed(1, 3), granges.All[int]()},
+			Want:   "[(-∞..+∞)]",
+		},
+		{Ranges: nil, Want: "[]"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, fmt.Sprint(granges.Coalesce(tt.Ranges)), "Coalesce(%v)", tt.Ranges)
+	}
+}
+
+func TestUnionAll(t *testing.T) {
+	a := granges.NewRangeSet(granges.Closed(1, 3), granges.ClosedOpen(10, 12), granges.AtLeast(40))
+	b := granges.NewRangeSet(granges.Open(2, 5), granges.ClosedOpen(12, 15), granges.OpenClosed(18, 20))
+	c := granges.NewRangeSet(granges.ClosedOpen(15, 18), granges.Singleton(30))
+	union := granges.UnionAll([]granges.RangeSet[int]{a, b, {}, c})
+	assert.EqualValues(t, "{[1..5), [10..18), (18..20], [30..30], [40..+∞)}", union.String())
+
+	// the sets are left unchanged
+	assert.EqualValues(t, "{[1..3], [10..12), [40..+∞)}", a.String())
+	assert.EqualValues(t, "{(2..5), [12..15), (18..20]}", b.String())
+	assert.EqualValues(t, "{[15..18), [30..30]}", c.String())
+
+	// the same as adding the sets to one another
+	var folded granges.RangeSet[int]
+	for _, s := range []granges.RangeSet[int]{a, b, c} {
+		for _, r := range s.AsRanges() {
+			folded.Add(r)
+		}
+	}
+	assert.EqualValues(t, folded.AsRanges(), union.AsRanges())
+
+	assert.EqualValues(t, "{[1..3], [10..12), [40..+∞)}", granges.UnionAll([]granges.RangeSet[int]{a}).String())
+	assert.True(t, granges.UnionAll([]granges.RangeSet[int]{{}, {}}).IsEmpty())
+	assert.True(t, granges.UnionAll[int](nil).IsEmpty())
+
+	// sets of another ordering than the first nonempty one are skipped
+	days := granges.UnionAll([]granges.RangeSet[time.Time]{
+		{},
+		granges.NewRangeSet(byTime.Closed(day(0), day(2))),
+		granges.NewRangeSet(granges.Closed(day(1), day(5))),
+		granges.NewRangeSet(byTime.Closed(day(2), day(3))),
+	})
+	assert.EqualValues(t, []granges.Range[time.Time]{byTime.Closed(day(0), day(3))}, days.AsRanges())
+}
+
+func BenchmarkUnionAll(b *testing.B) {
+	// 64 shards of 10k members each, interleaved so that every member of a
+	// shard lies between members of the others
+	shards := make([]granges.RangeSet[int], 64)
+	for i := range shards {
+		members := make([]granges.RangeSet[int], 10000)
+		for j := range members {
+			lower := j*1000 + i*10
+			members[j] = granges.NewRangeSet(granges.ClosedOpen(lower, lower+5))
+		}
+		shards[i] = granges.UnionAll(members)
+	}
+
+	b.Run("UnionAll", func(b *testing.B) {
+		for range b.N {
+			granges.UnionAll(shards)
+		}
+	})
+	b.Run("pairwise", func(b *testing.B) {
+		for range b.N {
+			var union granges.RangeSet[int]
+			for _, s := range shards {
+				union = granges.UnionAll([]granges.RangeSet[int]{union, s})
+			}
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package granges
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// SubsliceWithin returns the half-open index window [lo, hi) of the elements
+// in sorted that are contained in r, so that sorted[lo:hi] is exactly the
+// run of contained values. Both indices are found with a binary search over
+// the cuts of r, thus an open bound skips every element equal to its
+// endpoint while a closed bound keeps them all.
+//
+// The slice must be sorted in ascending order; the result is undefined
+// otherwise. An empty window (lo == hi) is returned for invalid or empty
+// ranges, and for ranges containing none of the elements.
+func SubsliceWithin[C any](sorted []C, r Range[C]) (lo, hi int) {
+	if r.IsInvalid() {
+		return 0, 0
+	}
+	lo = sort.Search(len(sorted), func(i int) bool {
+		return r.lowerBound.IsLessThan(sorted[i])
+	})
+	hi = sort.Search(len(sorted), func(i int) bool {
+		return r.upperBound.IsLessThan(sorted[i])
+	})
+	return lo, hi
+}
+
+// Retain returns the values contained in r, preserving their order. The input
+// slice is not modified; see RetainInPlace.
+func Retain[C any](values []C, r Range[C]) []C {
+	var retained []C
+	for _, value := range values {
+		if r.Contains(value) {
+			retained = append(retained, value)
+		}
+	}
+	return retained
+}
+
+// Reject returns the values not contained in r, preserving their order. The
+// input slice is not modified; see RejectInPlace.
+func Reject[C any](values []C, r Range[C]) []C {
+	var rejected []C
+	for _, value := range values {
+		if !r.Contains(value) {
+			rejected = append(rejected, value)
+		}
+	}
+	return rejected
+}
+
+// RetainInPlace removes the values not contained in r from values, preserving
+// the order of the others, and returns the modified slice, which shares the
+// backing array of values. As with slices.DeleteFunc, the elements between the
+// new length and the original length are zeroed.
+func RetainInPlace[C any](values []C, r Range[C]) []C {
+	return slices.DeleteFunc(values, r.NotPredicate())
+}
+
+// RejectInPlace removes the values contained in r from values, preserving the
+// order of the others, and returns the modified slice, which shares the
+// backing array of values. As with slices.DeleteFunc, the elements between the
+// new length and the original length are zeroed.
+func RejectInPlace[C any](values []C, r Range[C]) []C {
+	return slices.DeleteFunc(values, r.AsPredicate())
+}
+
+// FilterSeq returns a sequence of the values yielded by seq that are contained
+// in r, preserving their order. The sequence is lazy: values are pulled from
+// seq as the returned sequence is iterated.
+func FilterSeq[C any](seq iter.Seq[C], r Range[C]) iter.Seq[C] {
+	return func(yield func(C) bool) {
+		for value := range seq {
+			if r.Contains(value) && !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// By returns a predicate reporting whether the key extracted from a record is
+// contained in r. It allows ranges over the fields of records, for example to
+// select the records whose CreatedAt falls in a time window.
+func By[T, C any](r Range[C], key func(T) C) func(T) bool {
+	return func(record T) bool {
+		return r.Contains(key(record))
+	}
+}
+
+// FilterBy returns the records whose key is contained in r, preserving their
+// order. The input slice is not modified.
+func FilterBy[T, C any](records []T, r Range[C], key func(T) C) []T {
+	var filtered []T
+	for _, record := range records {
+		if r.Contains(key(record)) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// CountBy returns the number of records whose key is contained in r.
+func CountBy[T, C any](records []T, r Range[C], key func(T) C) int {
+	count := 0
+	for _, record := range records {
+		if r.Contains(key(record)) {
+			count++
+		}
+	}
+	return count
+}
+
+// PartitionBy splits records into those whose key is contained in r and those
+// whose key is not, preserving their order. The input slice is not modified.
+func PartitionBy[T, C any](records []T, r Range[C], key func(T) C) (in, out []T) {
+	for _, record := range records {
+		if r.Contains(key(record)) {
+			in = append(in, record)
+		} else {
+			out = append(out, record)
+		}
+	}
+	return in, out
+}
+
+// GroupBy assigns every record to the bucket containing its key, returning the
+// records of each bucket keyed by the bucket's index in buckets. Whether a key
+// equal to a bucket endpoint belongs to that bucket follows the bound type of
+// the endpoint, so buckets such as [0..10), [10..20) assign 10 to the second
+// one only.
+//
+// If buckets overlap, a record is assigned to the first bucket containing it.
+// Records contained in no bucket are dropped, and buckets without records
+// have no entry in the returned map.
+func GroupBy[T, C any](records []T, buckets []Range[C], key func(T) C) map[int][]T {
+	groups := make(map[int][]T)
+	for _, record := range records {
+		k := key(record)
+		for i := range buckets {
+			if buckets[i].Contains(k) {
+				groups[i] = append(groups[i], record)
+				break
+			}
+		}
+	}
+	return groups
+}
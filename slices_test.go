@@ -0,0 +1,181 @@
+package granges_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestSubsliceWithin(t *testing.T) {
+	sorted := []int{1, 2, 3, 3, 3, 5, 7, 7, 9}
+
+	tests := []struct {
+		R      granges.Range[int]
+		Lo, Hi int
+	}{
+		{R: granges.Closed(3, 7), Lo: 2, Hi: 8},
+		{R: granges.Open(3, 7), Lo: 5, Hi: 6},
+		{R: granges.ClosedOpen(3, 7), Lo: 2, Hi: 6},
+		{R: granges.OpenClosed(3, 7), Lo: 5, Hi: 8},
+		{R: granges.Singleton(3), Lo: 2, Hi: 5},
+		{R: granges.Singleton(4), Lo: 5, Hi: 5},
+		{R: granges.ClosedOpen(3, 3), Lo: 2, Hi: 2},
+		{R: granges.OpenClosed(3, 3), Lo: 5, Hi: 5},
+		{R: granges.LessThan(3), Lo: 0, Hi: 2},
+		{R: granges.AtMost(3), Lo: 0, Hi: 5},
+		{R: granges.GreaterThan(7), Lo: 8, Hi: 9},
+		{R: granges.AtLeast(7), Lo: 6, Hi: 9},
+		{R: granges.All[int](), Lo: 0, Hi: 9},
+		{R: granges.Closed(10, 20), Lo: 9, Hi: 9},
+		{R: granges.Closed(-5, 0), Lo: 0, Hi: 0},
+		{R: granges.Invalid[int](), Lo: 0, Hi: 0},
+	}
+
+	for _, tt := range tests {
+		lo, hi := granges.SubsliceWithin(sorted, tt.R)
+		if lo != tt.Lo || hi != tt.Hi {
+			t.Errorf("SubsliceWithin(%v) = [%d, %d), want [%d, %d)", tt.R, lo, hi, tt.Lo, tt.Hi)
+		}
+		for _, v := range sorted[lo:hi] {
+			assert.True(t, tt.R.Contains(v))
+		}
+	}
+
+	lo, hi := granges.SubsliceWithin([]int{}, granges.All[int]())
+	assert.EqualValues(t, 0, lo)
+	assert.EqualValues(t, 0, hi)
+}
+
+func TestRetain(t *testing.T) {
+	values := []int{7, 1, 5, 3, 9, 3}
+	assert.Equal(t, []int{5, 3, 3}, granges.Retain(values, granges.Closed(3, 5)))
+	assert.Equal(t, []int{5}, granges.Retain(values, granges.Open(3, 7)))
+	assert.Equal(t, values, granges.Retain(values, granges.All[int]()))
+	assert.Empty(t, granges.Retain(values, granges.ClosedOpen(3, 3)))
+	assert.Empty(t, granges.Retain(values, granges.Invalid[int]()))
+	assert.Empty(t, granges.Retain(nil, granges.All[int]()))
+	assert.Equal(t, []int{7, 1, 5, 3, 9, 3}, values)
+}
+
+func TestReject(t *testing.T) {
+	values := []int{7, 1, 5, 3, 9, 3}
+	assert.Equal(t, []int{7, 1, 9}, granges.Reject(values, granges.Closed(3, 5)))
+	assert.Equal(t, []int{7, 1, 3, 9, 3}, granges.Reject(values, granges.Open(3, 7)))
+	assert.Equal(t, values, granges.Reject(values, granges.ClosedOpen(3, 3)))
+	assert.Empty(t, granges.Reject(values, granges.All[int]()))
+	assert.Equal(t, []int{7, 1, 5, 3, 9, 3}, values)
+}
+
+func TestRetainInPlace(t *testing.T) {
+	values := []int{7, 1, 5, 3, 9, 3}
+	retained := granges.RetainInPlace(values, granges.Closed(3, 5))
+	assert.Equal(t, []int{5, 3, 3}, retained)
+	assert.Same(t, &values[0], &retained[0])
+	assert.Equal(t, []int{5, 3, 3, 0, 0, 0}, values)
+
+	assert.Equal(t, []int{1, 2}, granges.RetainInPlace([]int{1, 2}, granges.All[int]()))
+	assert.Empty(t, granges.RetainInPlace([]int{1, 2}, granges.ClosedOpen(1, 1)))
+}
+
+func TestRejectInPlace(t *testing.T) {
+	values := []int{7, 1, 5, 3, 9, 3}
+	rejected := granges.RejectInPlace(values, granges.Closed(3, 5))
+	assert.Equal(t, []int{7, 1, 9}, rejected)
+	assert.Same(t, &values[0], &rejected[0])
+
+	assert.Equal(t, []int{1, 2}, granges.RejectInPlace([]int{1, 2}, granges.ClosedOpen(1, 1)))
+	assert.Empty(t, granges.RejectInPlace([]int{1, 2}, granges.All[int]()))
+}
+
+func TestFilterSeq(t *testing.T) {
+	seq := slices.Values([]int{7, 1, 5, 3, 9, 3})
+	assert.Equal(t, []int{5, 3, 3}, slices.Collect(granges.FilterSeq(seq, granges.Closed(3, 5))))
+	assert.Equal(t, []int{7, 1, 5, 3, 9, 3}, slices.Collect(granges.FilterSeq(seq, granges.All[int]())))
+	assert.Empty(t, slices.Collect(granges.FilterSeq(seq, granges.ClosedOpen(3, 3))))
+
+	pulled := 0
+	for v := range granges.FilterSeq(countingSeq([]int{7, 1, 5, 3, 9, 3}, &pulled), granges.Closed(3, 5)) {
+		if v == 3 {
+			break
+		}
+	}
+	assert.EqualValues(t, 4, pulled)
+}
+
+type record struct {
+	ID        string
+	CreatedAt int64
+}
+
+func createdAt(r record) int64 { return r.CreatedAt }
+
+var records = []record{
+	{ID: "a", CreatedAt: 5},
+	{ID: "b", CreatedAt: 10},
+	{ID: "c", CreatedAt: 15},
+	{ID: "d", CreatedAt: 20},
+	{ID: "e", CreatedAt: 25},
+}
+
+func recordIDs(rs []record) []string {
+	ids := make([]string, 0, len(rs))
+	for _, r := range rs {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestBy(t *testing.T) {
+	pred := granges.By(granges.ClosedOpen[int64](10, 20), createdAt)
+	assert.False(t, pred(records[0]))
+	assert.True(t, pred(records[1]))
+	assert.True(t, pred(records[2]))
+	assert.False(t, pred(records[3]))
+}
+
+func TestFilterBy(t *testing.T) {
+	assert.EqualValues(t, []string{"b", "c", "d"},
+		recordIDs(granges.FilterBy(records, granges.Closed[int64](10, 20), createdAt)))
+	assert.EqualValues(t, []string{"c"},
+		recordIDs(granges.FilterBy(records, granges.Open[int64](10, 20), createdAt)))
+	assert.Empty(t, granges.FilterBy(records, granges.ClosedOpen[int64](10, 10), createdAt))
+	assert.Len(t, granges.FilterBy(records, granges.All[int64](), createdAt), len(records))
+}
+
+func TestCountBy(t *testing.T) {
+	assert.EqualValues(t, 3, granges.CountBy(records, granges.AtLeast[int64](15), createdAt))
+	assert.EqualValues(t, 2, granges.CountBy(records, granges.GreaterThan[int64](15), createdAt))
+	assert.EqualValues(t, 0, granges.CountBy(records, granges.LessThan[int64](5), createdAt))
+	assert.EqualValues(t, 0, granges.CountBy(nil, granges.All[int64](), createdAt))
+}
+
+func TestPartitionBy(t *testing.T) {
+	in, out := granges.PartitionBy(records, granges.OpenClosed[int64](10, 20), createdAt)
+	assert.EqualValues(t, []string{"c", "d"}, recordIDs(in))
+	assert.EqualValues(t, []string{"a", "b", "e"}, recordIDs(out))
+}
+
+func TestGroupBy(t *testing.T) {
+	buckets := []granges.Range[int64]{
+		granges.ClosedOpen[int64](0, 10),
+		granges.ClosedOpen[int64](10, 20),
+		granges.ClosedOpen[int64](20, 30),
+		granges.AtLeast[int64](100),
+	}
+	groups := granges.GroupBy(records, buckets, createdAt)
+	assert.Len(t, groups, 3)
+	assert.EqualValues(t, []string{"a"}, recordIDs(groups[0]))
+	assert.EqualValues(t, []string{"b", "c"}, recordIDs(groups[1]))
+	assert.EqualValues(t, []string{"d", "e"}, recordIDs(groups[2]))
+
+	// records go to the first bucket containing them, or nowhere
+	groups = granges.GroupBy(records, []granges.Range[int64]{
+		granges.Closed[int64](10, 20),
+		granges.Closed[int64](15, 25),
+	}, createdAt)
+	assert.EqualValues(t, []string{"b", "c", "d"}, recordIDs(groups[0]))
+	assert.EqualValues(t, []string{"e"}, recordIDs(groups[1]))
+}
@@ -0,0 +1,105 @@
+package granges
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+const postgresEmpty = "empty"
+
+// Scan implements sql.Scanner for ranges of integer and float types, reading
+// the text format of PostgreSQL range types such as int4range, int8range and
+// numrange: [1,5), (,10] or (1.5,), where an omitted endpoint means that the
+// range is unbounded on that side, whatever its bracket. The literal "empty"
+// yields the empty range [0..0), as PostgreSQL does not keep the endpoints of
+// empty ranges.
+//
+// An error will be returned, leaving this range unchanged, if src is neither
+// a string nor a []byte, if it is malformed, if an endpoint does not fit in C,
+// or if the range would be invalid.
+func (r *Range[C]) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into range", src)
+	}
+	order, err := r.decodingOrder()
+	if err != nil {
+		return err
+	}
+	decoded, err := parsePostgres(text, order)
+	if err != nil {
+		return err
+	}
+	*r = decoded
+	return nil
+}
+
+// Value implements driver.Valuer for ranges of integer and float types,
+// writing the text format of PostgreSQL range types read by Scan. Unbounded
+// sides are written with an omitted endpoint, such as (,10], and empty ranges
+// as "empty".
+//
+// An error will be returned if this range is invalid, or if C is neither an
+// integer nor a float type.
+func (r Range[C]) Value() (driver.Value, error) {
+	if r.IsInvalid() {
+		return nil, fmt.Errorf("cannot convert invalid range to a database value")
+	}
+	if _, err := parseNumber[C]("0"); err != nil {
+		return nil, err
+	}
+	if r.IsEmpty() {
+		return postgresEmpty, nil
+	}
+	var sb strings.Builder
+	if r.HasLowerBound() && r.LowerBoundType() == CLOSED {
+		sb.WriteByte('[')
+	} else {
+		sb.WriteByte('(')
+	}
+	if r.HasLowerBound() {
+		fmt.Fprint(&sb, r.LowerEndpoint())
+	}
+	sb.WriteByte(',')
+	if r.HasUpperBound() {
+		fmt.Fprint(&sb, r.UpperEndpoint())
+	}
+	if r.HasUpperBound() && r.UpperBoundType() == CLOSED {
+		sb.WriteByte(']')
+	} else {
+		sb.WriteByte(')')
+	}
+	return sb.String(), nil
+}
+
+// parsePostgres parses s in the text format of PostgreSQL range types, see
+// Range.Scan, into a range whose cuts carry order.
+func parsePostgres[C any](s string, order *ordering[C]) (Range[C], error) {
+	zero, err := parseNumber[C]("0")
+	if err != nil {
+		return Invalid[C](), err
+	}
+	text := strings.TrimSpace(s)
+	if strings.EqualFold(text, postgresEmpty) {
+		return newRange(zero, CLOSED, zero, OPEN, order)
+	}
+	return parseInterval(s, func(text string) (C, error) {
+		return parseNumber[C](unquotePostgres(text))
+	}, order)
+}
+
+// unquotePostgres returns text without surrounding whitespace and the double
+// quotes PostgreSQL may put around an endpoint.
+func unquotePostgres(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
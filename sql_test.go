@@ -0,0 +1,95 @@
+package granges_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+var (
+	_ sql.Scanner   = (*granges.Range[int])(nil)
+	_ driver.Valuer = granges.Range[int]{}
+)
+
+func TestRange_Value(t *testing.T) {
+	tests := []struct {
+		R    granges.Range[int]
+		Want string
+	}{
+		{R: granges.ClosedOpen(1, 5), Want: "[1,5)"},
+		{R: granges.Closed(-1, 5), Want: "[-1,5]"},
+		{R: granges.Open(1, 5), Want: "(1,5)"},
+		{R: granges.OpenClosed(1, 5), Want: "(1,5]"},
+		{R: granges.AtMost(10), Want: "(,10]"},
+		{R: granges.LessThan(10), Want: "(,10)"},
+		{R: granges.AtLeast(1), Want: "[1,)"},
+		{R: granges.GreaterThan(1), Want: "(1,)"},
+		{R: granges.All[int](), Want: "(,)"},
+		{R: granges.ClosedOpen(4, 4), Want: "empty"},
+	}
+
+	for _, tt := range tests {
+		value, err := tt.R.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.Want, value, "%v.Value()", tt.R)
+
+		var scanned granges.Range[int]
+		assert.NoError(t, scanned.Scan(value))
+		if !tt.R.IsEmpty() {
+			assert.True(t, tt.R.Equal(scanned), "Scan(%s) = %v, want %v", value, scanned, tt.R)
+		}
+	}
+
+	value, err := granges.Closed(1.5, 2.25).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "[1.5,2.25]", value)
+
+	_, err = granges.Invalid[int]().Value()
+	assert.Error(t, err)
+	_, err = granges.Closed("a", "b").Value()
+	assert.Error(t, err)
+}
+
+func TestRange_Scan(t *testing.T) {
+	tests := []struct {
+		Src  any
+		Want granges.Range[int64]
+	}{
+		{Src: "[1,5)", Want: granges.ClosedOpen[int64](1, 5)},
+		{Src: []byte("(1,5]"), Want: granges.OpenClosed[int64](1, 5)},
+		{Src: " [ -3 , 5 ] ", Want: granges.Closed[int64](-3, 5)},
+		{Src: `["1","5")`, Want: granges.ClosedOpen[int64](1, 5)},
+		{Src: "(,10]", Want: granges.AtMost[int64](10)},
+		{Src: "[,10]", Want: granges.AtMost[int64](10)},
+		{Src: "[1,]", Want: granges.AtLeast[int64](1)},
+		{Src: "(,)", Want: granges.All[int64]()},
+		{Src: "empty", Want: granges.ClosedOpen[int64](0, 0)},
+		{Src: []byte("EMPTY"), Want: granges.ClosedOpen[int64](0, 0)},
+	}
+
+	for _, tt := range tests {
+		var r granges.Range[int64]
+		assert.NoError(t, r.Scan(tt.Src), "Scan(%v)", tt.Src)
+		assert.True(t, tt.Want.Equal(r), "Scan(%v) = %v, want %v", tt.Src, r, tt.Want)
+	}
+
+	var f granges.Range[float64]
+	assert.NoError(t, f.Scan("[0.5,1e3)"))
+	assert.True(t, granges.ClosedOpen(0.5, 1000.0).Equal(f))
+	assert.NoError(t, f.Scan("[-Infinity,0]"))
+	assert.True(t, granges.Closed(math.Inf(-1), 0).Equal(f))
+
+	for _, src := range []any{"(5,4)", "[1;5)", "1,5", "[a,5)", "[1,5.5)", "", "e", nil, 42} {
+		r := granges.Closed[int64](7, 9)
+		assert.Error(t, r.Scan(src), "Scan(%v)", src)
+		assert.True(t, granges.Closed[int64](7, 9).Equal(r), "Scan(%v) changed the range to %v", src, r)
+	}
+
+	var s granges.Range[string]
+	assert.Error(t, s.Scan("[a,b)"))
+}
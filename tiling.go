@@ -0,0 +1,153 @@
+package granges
+
+import (
+	"fmt"
+	"slices"
+)
+
+// TilingDefect classifies why a set of pieces fails to tile a target range.
+type TilingDefect int
+
+const (
+	TilingGap     TilingDefect = iota // some values of the target are covered by no piece
+	TilingOverlap                     // some values are covered by more than one piece
+	TilingSpill                       // a piece covers values outside the target
+)
+
+func (d TilingDefect) String() string {
+	switch d {
+	case TilingGap:
+		return "gap"
+	case TilingOverlap:
+		return "overlap"
+	case TilingSpill:
+		return "spill"
+	default:
+		return "unknown"
+	}
+}
+
+// TilingError describes the first defect found by TilesExactly.
+//
+// I and J are the indices (in the pieces slice passed to TilesExactly) of the
+// pieces involved. For a gap, I is the piece below the gap and J the piece
+// above it, either of them is -1 when the gap touches the lower or upper edge
+// of the target instead. For an overlap, I and J are the two overlapping
+// pieces. For a spill, I is the offending piece and J is -1.
+//
+// Region is the uncovered range for a gap, the doubly covered range for an
+// overlap, and the offending piece for a spill.
+//
+// BoundTypeMismatch reports that the seam between I and J would be perfect if
+// not for the bound types at a shared endpoint: both open (leaving that single
+// point uncovered) or both closed (covering it twice).
+type TilingError[C any] struct {
+	Defect            TilingDefect
+	I, J              int
+	Region            Range[C]
+	BoundTypeMismatch bool
+}
+
+func (e *TilingError[C]) Error() string {
+	switch e.Defect {
+	case TilingSpill:
+		return fmt.Sprintf("piece %d %s extends beyond the target", e.I, e.Region)
+	default:
+		msg := fmt.Sprintf("%s between %s and %s at %s",
+			e.Defect, e.describePiece(e.I, "lower edge"), e.describePiece(e.J, "upper edge"), e.Region)
+		if e.BoundTypeMismatch {
+			msg += " (incompatible bound types at the seam)"
+		}
+		return msg
+	}
+}
+
+func (e *TilingError[C]) describePiece(i int, edge string) string {
+	if i < 0 {
+		return "the target's " + edge
+	}
+	return fmt.Sprintf("piece %d", i)
+}
+
+// TilesExactly reports whether pieces partition target exactly: every value
+// of target is contained in exactly one piece, and no piece contains a value
+// outside target. The order of pieces does not matter, and empty pieces are
+// ignored since they contain no values.
+//
+// When the pieces do not tile the target, false is returned together with a
+// *TilingError describing the first defect in ascending order of the pieces.
+// An error is also returned if target or any of the pieces is invalid.
+func TilesExactly[C any](pieces []Range[C], target Range[C]) (bool, error) {
+	if target.IsInvalid() {
+		return false, fmt.Errorf("invalid target range")
+	}
+
+	order := make([]int, 0, len(pieces))
+	for i, piece := range pieces {
+		if piece.IsInvalid() {
+			return false, fmt.Errorf("piece %d is an invalid range", i)
+		}
+		if !piece.IsEmpty() {
+			order = append(order, i)
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if c := pieces[a].lowerBound.Compare(pieces[b].lowerBound); c != 0 {
+			return c
+		}
+		return pieces[a].upperBound.Compare(pieces[b].upperBound)
+	})
+
+	for _, i := range order {
+		if !target.Encloses(pieces[i]) {
+			return false, &TilingError[C]{Defect: TilingSpill, I: i, J: -1, Region: pieces[i]}
+		}
+	}
+
+	// covered is the upper cut reached so far, and last the piece reaching it
+	covered, last := target.lowerBound, -1
+	for _, i := range order {
+		piece := pieces[i]
+		switch cmp := covered.Compare(piece.lowerBound); {
+		case cmp < 0:
+			gap, _ := create(covered, piece.lowerBound)
+			return false, &TilingError[C]{
+				Defect:            TilingGap,
+				I:                 last,
+				J:                 i,
+				Region:            gap,
+				BoundTypeMismatch: sameEndpoint(covered, piece.lowerBound),
+			}
+		case cmp > 0:
+			overlap := pieces[last].Intersection(piece)
+			return false, &TilingError[C]{
+				Defect:            TilingOverlap,
+				I:                 last,
+				J:                 i,
+				Region:            overlap,
+				BoundTypeMismatch: sameEndpoint(covered, piece.lowerBound),
+			}
+		}
+		covered, last = piece.upperBound, i
+	}
+
+	if covered.Compare(target.upperBound) < 0 {
+		gap, _ := create(covered, target.upperBound)
+		return false, &TilingError[C]{
+			Defect:            TilingGap,
+			I:                 last,
+			J:                 -1,
+			Region:            gap,
+			BoundTypeMismatch: sameEndpoint(covered, target.upperBound),
+		}
+	}
+	return true, nil
+}
+
+// sameEndpoint returns true if both cuts are bounded and cut the number line
+// at the same value, possibly on different sides of it.
+func sameEndpoint[C any](a, b Cut[C]) bool {
+	ea, errA := a.Endpoint()
+	eb, errB := b.Endpoint()
+	return errA == nil && errB == nil && a.orderWith(b.order).compare(ea, eb) == 0
+}
@@ -0,0 +1,143 @@
+package granges_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestTilesExactly(t *testing.T) {
+	target := granges.ClosedOpen(0, 10)
+
+	ok, err := granges.TilesExactly([]granges.Range[int]{
+		granges.ClosedOpen(5, 10),
+		granges.ClosedOpen(0, 3),
+		granges.ClosedOpen(3, 5),
+		granges.ClosedOpen(7, 7), // empty pieces are ignored
+	}, target)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = granges.TilesExactly([]granges.Range[int]{granges.All[int]()}, granges.All[int]())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = granges.TilesExactly([]granges.Range[int]{
+		granges.LessThan(0), granges.Closed(0, 5), granges.GreaterThan(5),
+	}, granges.All[int]())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = granges.TilesExactly(nil, granges.ClosedOpen(3, 3))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestTilesExactly_defects(t *testing.T) {
+	target := granges.ClosedOpen(0, 10)
+
+	tests := []struct {
+		Name     string
+		Pieces   []granges.Range[int]
+		Target   granges.Range[int]
+		Defect   granges.TilingDefect
+		I, J     int
+		Region   granges.Range[int]
+		Mismatch bool
+	}{
+		{
+			Name:   "gap between pieces",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(0, 4), granges.ClosedOpen(6, 10)},
+			Target: target, Defect: granges.TilingGap, I: 0, J: 1,
+			Region: granges.ClosedOpen(4, 6),
+		},
+		{
+			Name:   "both open at seam",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(0, 5), granges.Open(5, 10)},
+			Target: target, Defect: granges.TilingGap, I: 0, J: 1,
+			Region: granges.Singleton(5), Mismatch: true,
+		},
+		{
+			Name:   "both closed at seam",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(5, 10), granges.Closed(0, 5)},
+			Target: target, Defect: granges.TilingOverlap, I: 1, J: 0,
+			Region: granges.Singleton(5), Mismatch: true,
+		},
+		{
+			Name:   "overlap",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(0, 6), granges.ClosedOpen(4, 10)},
+			Target: target, Defect: granges.TilingOverlap, I: 0, J: 1,
+			Region: granges.ClosedOpen(4, 6),
+		},
+		{
+			Name:   "enclosed overlap",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(0, 10), granges.Closed(3, 4)},
+			Target: target, Defect: granges.TilingOverlap, I: 0, J: 1,
+			Region: granges.Closed(3, 4),
+		},
+		{
+			Name:   "spill",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(0, 5), granges.Closed(5, 10)},
+			Target: target, Defect: granges.TilingSpill, I: 1, J: -1,
+			Region: granges.Closed(5, 10),
+		},
+		{
+			Name:   "gap at lower edge",
+			Pieces: []granges.Range[int]{granges.ClosedOpen(2, 10)},
+			Target: target, Defect: granges.TilingGap, I: -1, J: 0,
+			Region: granges.ClosedOpen(0, 2),
+		},
+		{
+			Name:   "gap at upper edge",
+			Pieces: []granges.Range[int]{granges.Open(0, 5), granges.ClosedOpen(5, 10)},
+			Target: granges.Open(0, 20), Defect: granges.TilingGap, I: 1, J: -1,
+			Region: granges.ClosedOpen(10, 20),
+		},
+		{
+			Name:   "no pieces",
+			Pieces: nil,
+			Target: target, Defect: granges.TilingGap, I: -1, J: -1,
+			Region: target,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			ok, err := granges.TilesExactly(tt.Pieces, tt.Target)
+			assert.False(t, ok)
+			var tilingErr *granges.TilingError[int]
+			if !errors.As(err, &tilingErr) {
+				t.Fatalf("TilesExactly() error = %v, want *TilingError", err)
+			}
+			assert.EqualValues(t, tt.Defect, tilingErr.Defect)
+			assert.EqualValues(t, tt.I, tilingErr.I)
+			assert.EqualValues(t, tt.J, tilingErr.J)
+			assert.True(t, tt.Region.Equal(tilingErr.Region), "region %v, want %v", tilingErr.Region, tt.Region)
+			assert.EqualValues(t, tt.Mismatch, tilingErr.BoundTypeMismatch)
+		})
+	}
+}
+
+func TestTilesExactly_invalid(t *testing.T) {
+	_, err := granges.TilesExactly([]granges.Range[int]{granges.Closed(0, 1)}, granges.Invalid[int]())
+	assert.Error(t, err)
+
+	_, err = granges.TilesExactly([]granges.Range[int]{granges.Open(1, 1)}, granges.Closed(0, 1))
+	assert.Error(t, err)
+}
+
+func TestTilingError_Error(t *testing.T) {
+	_, err := granges.TilesExactly([]granges.Range[int]{
+		granges.ClosedOpen(0, 5), granges.Open(5, 10),
+	}, granges.ClosedOpen(0, 10))
+	assert.EqualError(t, err, "gap between piece 0 and piece 1 at [5..5] (incompatible bound types at the seam)")
+
+	_, err = granges.TilesExactly([]granges.Range[int]{granges.Closed(0, 10)}, granges.ClosedOpen(0, 10))
+	assert.EqualError(t, err, "piece 0 [0..10] extends beyond the target")
+
+	_, err = granges.TilesExactly([]granges.Range[int]{granges.ClosedOpen(1, 10)}, granges.ClosedOpen(0, 10))
+	assert.EqualError(t, err, "gap between the target's lower edge and piece 0 at [0..1)")
+}
@@ -0,0 +1,133 @@
+package granges
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	timelineCovered = '█'
+	timelineGap     = '·'
+	timelinePoint   = '|'
+)
+
+// TimelineRow is a labelled row of ranges drawn by Visualize and
+// VisualizeWith.
+type TimelineRow[C any] struct {
+	Label  string
+	Ranges []Range[C]
+}
+
+// Visualize draws rows as an ASCII timeline of width columns spanning bounds,
+// preceded by an axis header showing the endpoints of bounds.
+//
+// Each column of a row is drawn as '█' if some range of the row covers it and
+// '·' otherwise. The columns holding the endpoints of a range are marked by
+// the bracket of their bound type, '[' or '(' for the lower endpoint and ']'
+// or ')' for the upper one, and a range narrower than a single column is
+// drawn as '|'. Ranges are clipped to bounds, so unbounded ranges are drawn
+// up to the edges of the timeline without endpoint markers.
+//
+// An error will be returned if bounds is invalid, unbounded or has no width,
+// if width is not positive, or if writing to w fails.
+func Visualize[C Number](w io.Writer, rows []TimelineRow[C], bounds Range[C], width int) error {
+	return VisualizeWith(w, rows, bounds, width,
+		func(v C) float64 { return float64(v) },
+		func(v C) string { return fmt.Sprint(v) })
+}
+
+// VisualizeWith draws rows like Visualize, for ranges of any ordered type. The
+// columns of values are computed from their position on the timeline, which
+// must increase with the values, and the endpoints of bounds are shown in the
+// axis header as returned by format. For example, time ranges can be drawn
+// with:
+//
+//	granges.VisualizeWith(w, rows, bounds, 80,
+//		func(t time.Time) float64 { return float64(t.UnixNano()) },
+//		func(t time.Time) string { return t.Format(time.Kitchen) })
+//
+// An error will be returned if bounds is invalid, unbounded or has no width,
+// if width is not positive, or if writing to w fails.
+func VisualizeWith[C any](w io.Writer, rows []TimelineRow[C], bounds Range[C], width int, position func(C) float64, format func(C) string) error {
+	if width <= 0 {
+		return fmt.Errorf("invalid timeline width %d", width)
+	}
+	if bounds.IsInvalid() || !bounds.HasLowerBound() || !bounds.HasUpperBound() {
+		return fmt.Errorf("timeline bounds must be bounded: %s", bounds)
+	}
+	lower, upper := position(bounds.LowerEndpoint()), position(bounds.UpperEndpoint())
+	if !(lower < upper) {
+		return fmt.Errorf("timeline bounds must not be empty: %s", bounds)
+	}
+
+	labelWidth := 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(row.Label))
+	}
+
+	write := func(label string, line string) error {
+		padding := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(label))
+		_, err := fmt.Fprintf(w, "%s%s %s\n", label, padding, line)
+		return err
+	}
+
+	axis := timelineAxis(format(bounds.LowerEndpoint()), format(bounds.UpperEndpoint()), width)
+	if err := write("", axis); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := write(row.Label, timelineRow(row.Ranges, bounds, position, lower, upper, width)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func timelineAxis(lower, upper string, width int) string {
+	gap := width - utf8.RuneCountInString(lower) - utf8.RuneCountInString(upper)
+	return lower + strings.Repeat(" ", max(gap, 1)) + upper
+}
+
+func timelineRow[C any](ranges []Range[C], bounds Range[C], position func(C) float64, lower, upper float64, width int) string {
+	line := []rune(strings.Repeat(string(timelineGap), width))
+	scale := float64(width) / (upper - lower)
+
+	for _, r := range ranges {
+		if r.IsInvalid() || !r.IsConnected(bounds) {
+			continue
+		}
+		clipped := r.Intersection(bounds)
+		if clipped.IsEmpty() {
+			continue
+		}
+
+		start := int(math.Floor((position(clipped.LowerEndpoint()) - lower) * scale))
+		end := int(math.Ceil((position(clipped.UpperEndpoint())-lower)*scale)) - 1
+		start = min(max(start, 0), width-1)
+		end = min(max(end, start), width-1)
+
+		if start == end {
+			line[start] = timelinePoint
+			continue
+		}
+		for i := start; i <= end; i++ {
+			line[i] = timelineCovered
+		}
+		if clipped.lowerBound.Equal(r.lowerBound) {
+			line[start] = '('
+			if clipped.LowerBoundType() == CLOSED {
+				line[start] = '['
+			}
+		}
+		if clipped.upperBound.Equal(r.upperBound) {
+			line[end] = ')'
+			if clipped.UpperBoundType() == CLOSED {
+				line[end] = ']'
+			}
+		}
+	}
+	return string(line)
+}
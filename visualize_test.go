@@ -0,0 +1,82 @@
+package granges_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AyakuraYuki/granges"
+)
+
+func TestVisualize(t *testing.T) {
+	var buf bytes.Buffer
+	err := granges.Visualize(&buf, []granges.TimelineRow[int]{
+		{Label: "alice", Ranges: []granges.Range[int]{granges.ClosedOpen(0, 5), granges.OpenClosed(12, 18)}},
+		{Label: "bob", Ranges: []granges.Range[int]{granges.AtLeast(10)}},
+		{Label: "carol", Ranges: []granges.Range[int]{granges.Singleton(3), granges.LessThan(-5)}},
+		{Label: "dave", Ranges: []granges.Range[int]{granges.All[int]()}},
+		{Label: "eve"},
+	}, granges.ClosedOpen(0, 20), 20)
+	assert.NoError(t, err)
+	assert.EqualValues(t, ""+
+		"      0                 20\n"+
+		"alice [███)·······(████]··\n"+
+		"bob   ··········[█████████\n"+
+		"carol ···|················\n"+
+		"dave  ████████████████████\n"+
+		"eve   ····················\n",
+		buf.String())
+}
+
+func TestVisualize_resolution(t *testing.T) {
+	var buf bytes.Buffer
+	err := granges.Visualize(&buf, []granges.TimelineRow[float64]{
+		{Label: "x", Ranges: []granges.Range[float64]{granges.Closed(0.1, 0.2), granges.Open(5.0, 10.0)}},
+	}, granges.Closed(0.0, 10.0), 5)
+	assert.NoError(t, err)
+	assert.EqualValues(t, ""+
+		"  0  10\n"+
+		"x |·(█)\n",
+		buf.String())
+}
+
+func TestVisualizeWith(t *testing.T) {
+	position := func(t time.Time) float64 { return float64(t.Unix()) }
+	format := func(t time.Time) string { return t.Format(time.DateOnly) }
+
+	var buf bytes.Buffer
+	err := granges.VisualizeWith(&buf, []granges.TimelineRow[time.Time]{
+		{Label: "a", Ranges: []granges.Range[time.Time]{byTime.ClosedOpen(day(0), day(4)), byTime.AtLeast(day(15))}},
+		{Label: "b", Ranges: []granges.Range[time.Time]{byTime.Closed(day(10), day(10))}},
+	}, byTime.ClosedOpen(day(0), day(30)), 30, position, format)
+	assert.NoError(t, err)
+	assert.EqualValues(t, ""+
+		"  2024-01-01          2024-01-31\n"+
+		"a [██)···········[██████████████\n"+
+		"b ··········|···················\n",
+		buf.String())
+
+	buf.Reset()
+	assert.Error(t, granges.VisualizeWith(&buf, nil, byTime.Closed(day(1), day(1)), 10, position, format))
+	assert.Empty(t, buf.String())
+}
+
+func TestVisualize_invalid(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, granges.Visualize(&buf, nil, granges.Closed(0, 10), 0))
+	assert.Error(t, granges.Visualize(&buf, nil, granges.AtLeast(0), 10))
+	assert.Error(t, granges.Visualize(&buf, nil, granges.Invalid[int](), 10))
+	assert.Error(t, granges.Visualize(&buf, nil, granges.Singleton(1), 10))
+	assert.Empty(t, buf.String())
+
+	assert.Error(t, granges.Visualize(failingWriter{}, nil, granges.Closed(0, 10), 10))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}